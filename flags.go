@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strconv"
+)
+
+// The flag package has no int32 and float32 values, which the config uses
+// to match raylib. These adapters parse straight into the config fields.
+
+type int32Flag struct {
+	value *int32
+}
+
+func (f int32Flag) String() string {
+	if f.value == nil {
+		return "0"
+	}
+	return strconv.FormatInt(int64(*f.value), 10)
+}
+
+func (f int32Flag) Set(s string) error {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return err
+	}
+	*f.value = int32(v)
+	return nil
+}
+
+type float32Flag struct {
+	value *float32
+}
+
+func (f float32Flag) String() string {
+	if f.value == nil {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(*f.value), 'g', -1, 32)
+}
+
+func (f float32Flag) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return err
+	}
+	*f.value = float32(v)
+	return nil
+}
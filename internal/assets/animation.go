@@ -0,0 +1,33 @@
+package assets
+
+import (
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Animation is a sequence of frames taken from the ShipAtlas
+type Animation struct {
+	Frames []rl.Texture2D
+}
+
+// Function to load all the frames of an animation from the ShipAtlas
+// For the naming of the frames refer to the atlas package
+func LoadAnimation(name string) Animation {
+	names := ShipAtlas.Animations[name]
+	if len(names) == 0 {
+		rl.TraceLog(rl.LogError, "Animation %s not found in the atlas", name)
+		os.Exit(1)
+	}
+
+	animation := Animation{}
+	for _, frame := range names {
+		animation.Frames = append(animation.Frames, LoadTexture(frame))
+	}
+	return animation
+}
+
+// Returns the frame for the given step, starting over after the last one
+func (a Animation) Frame(step int) rl.Texture2D {
+	return a.Frames[step%len(a.Frames)]
+}
@@ -44,15 +44,6 @@ func LoadMusic(musicData []byte) rl.Music {
 	return rl.LoadMusicStreamFromMemory(".ogg", musicData, int32(len(musicData)))
 }
 
-// Function to load an embedded Sound file (sFX for laser, explosion etc.)
-// For the purpose of this game two assumptions are made:
-// - the sound type is OGG
-// - the sound file is in the assets/sounds folder of the embedded FS
-func LoadSound(soundData []byte) rl.Sound {
-	wave := rl.LoadWaveFromMemory(".ogg", soundData, int32(len(soundData)))
-	return rl.LoadSoundFromWave(wave)
-}
-
 func Font() rl.Font {
 	return LoadFont(fonts.Monogram_ttf)
 }
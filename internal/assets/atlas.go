@@ -1,76 +1,48 @@
 package assets
 
 import (
-	"encoding/xml"
-	"fmt"
+	"goinvaders/internal/assets/atlas"
 	"goinvaders/internal/assets/images"
 	"os"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-// The following two structures map the atlas xml file produced by TexturePacker
-// For detail look at the ships.xml file, it is quite straightforward to understand
-type sprite struct {
-	Name string `xml:"n,attr"`
-	X    uint   `xml:"x,attr"`
-	Y    uint   `xml:"y,attr"`
-	W    uint   `xml:"w,attr"`
-	H    uint   `xml:"h,attr"`
-}
-
-type textureAtlas struct {
-	Sprites []sprite `xml:"sprite"`
-}
-
 // This is the published Atlas structure that is used to get sprite images
 // Image is the actual SpriteSheet png file with all the sprites
 // Sprites is a map that returns an rl.Rectangle for each image name (the original filename)
 // It is used to get the Sub-Textures of each sprite from the full texture
+// Animations lists the sprite names of the frames of each animation
+// The xml parsing itself lives in the atlas package, which has no raylib dependency
 type Atlas struct {
-	Image   *rl.Image
-	Sprites map[string]rl.Rectangle
+	Image      *rl.Image
+	Sprites    map[string]rl.Rectangle
+	Animations map[string][]string
 }
 
 var ShipAtlas = NewAtlas(images.Ships_xml)
 
 func NewAtlas(xmlData []byte) *Atlas {
 
-	Ta := textureAtlas{}
-	err := xml.Unmarshal(xmlData, &Ta)
+	sheet, err := atlas.Parse(xmlData)
 	if err != nil {
 		rl.TraceLog(rl.LogError, "Could not unmarshal the ships.xml data: %s", err.Error())
 		os.Exit(1)
 	}
 
 	atlas := &Atlas{
-		Image:   LoadImage(images.Ships_png),
-		Sprites: make(map[string]rl.Rectangle),
+		Image:      LoadImage(images.Ships_png),
+		Sprites:    make(map[string]rl.Rectangle),
+		Animations: sheet.Animations,
 	}
-	for _, s := range Ta.Sprites {
-		atlas.Sprites[s.Name] = rl.Rectangle{
-			X:      float32(s.X),
-			Y:      float32(s.Y),
-			Width:  float32(s.W),
-			Height: float32(s.H),
+	for name, f := range sheet.Frames {
+		atlas.Sprites[name] = rl.Rectangle{
+			X:      f.X,
+			Y:      f.Y,
+			Width:  f.Width,
+			Height: f.Height,
 		}
 	}
 
 	return atlas
 }
-
-// The following functions get the respective image textures
-// They actually hide the file system structure so to create a layer of
-// abstraction, giving freedom to move images around without breakin the code.
-
-func GetAlienImage(alienType int32) rl.Texture2D {
-	name := fmt.Sprintf("alien_%d.png", alienType)
-	return LoadTexture(name)
-}
-
-func GetSpaceshipImage() rl.Texture2D {
-	return LoadTexture("spaceship.png")
-}
-func GetMysteryImage() rl.Texture2D {
-	return LoadTexture("mystery.png")
-}
@@ -0,0 +1,122 @@
+package atlas
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The following two structures map the atlas xml file produced by TexturePacker
+// For detail look at the ships.xml file, it is quite straightforward to understand
+type sprite struct {
+	Name string `xml:"n,attr"`
+	X    uint   `xml:"x,attr"`
+	Y    uint   `xml:"y,attr"`
+	W    uint   `xml:"w,attr"`
+	H    uint   `xml:"h,attr"`
+}
+
+type textureAtlas struct {
+	Sprites []sprite `xml:"sprite"`
+}
+
+// Frame is the area of a single sprite inside the sprite sheet
+type Frame struct {
+	X      float32
+	Y      float32
+	Width  float32
+	Height float32
+}
+
+// Sheet is the parsed content of an atlas xml file.
+// It does not depend on raylib, so the game simulation can use it to know
+// the size of each sprite without loading any texture.
+//
+// Sprites are grouped in animations by their name: "alien_1_a.png" and
+// "alien_1_b.png" are frames a and b of the "alien_1" animation.
+// A sprite without a frame letter, like "spaceship.png", is an animation
+// with a single frame. New frames only need to be added to the atlas.
+type Sheet struct {
+	Frames     map[string]Frame
+	Animations map[string][]string
+}
+
+// Animation names used by the game
+const (
+	SpaceshipSprite = "spaceship"
+	MysterySprite   = "mystery"
+	ExplosionSprite = "explosion"
+)
+
+func AlienSprite(alienType int32) string {
+	return fmt.Sprintf("alien_%d", alienType)
+}
+
+// Splits a sprite name into its animation name and frame letter
+func animationOf(name string) (string, string) {
+	base := strings.TrimSuffix(name, path.Ext(name))
+	n := len(base)
+	if n > 2 && base[n-2] == '_' && base[n-1] >= 'a' && base[n-1] <= 'z' {
+		return base[:n-2], base[n-1:]
+	}
+	return base, ""
+}
+
+func Parse(xmlData []byte) (*Sheet, error) {
+	ta := textureAtlas{}
+	if err := xml.Unmarshal(xmlData, &ta); err != nil {
+		return nil, fmt.Errorf("could not unmarshal the atlas data: %w", err)
+	}
+
+	sheet := &Sheet{
+		Frames:     make(map[string]Frame),
+		Animations: make(map[string][]string),
+	}
+	for _, s := range ta.Sprites {
+		sheet.Frames[s.Name] = Frame{
+			X:      float32(s.X),
+			Y:      float32(s.Y),
+			Width:  float32(s.W),
+			Height: float32(s.H),
+		}
+		animation, _ := animationOf(s.Name)
+		sheet.Animations[animation] = append(sheet.Animations[animation], s.Name)
+	}
+
+	for animation, frames := range sheet.Animations {
+		sort.Slice(frames, func(i, j int) bool {
+			_, fi := animationOf(frames[i])
+			_, fj := animationOf(frames[j])
+			return fi < fj
+		})
+		first := sheet.Frames[frames[0]]
+		for _, frame := range frames[1:] {
+			if f := sheet.Frames[frame]; f.Width != first.Width || f.Height != first.Height {
+				return nil, fmt.Errorf("frame %s has not the same size as the other frames of %s", frame, animation)
+			}
+		}
+	}
+	return sheet, nil
+}
+
+// MustParse is like Parse but panics if the data is invalid.
+// It is meant for the embedded atlas, which is known to be valid.
+func MustParse(xmlData []byte) *Sheet {
+	sheet, err := Parse(xmlData)
+	if err != nil {
+		panic(err)
+	}
+	return sheet
+}
+
+// Size returns the width and height of the frames of the named animation (0, 0 if missing)
+func (s *Sheet) Size(animation string) (float32, float32) {
+	frames := s.Animations[animation]
+	if len(frames) == 0 {
+		return 0, 0
+	}
+	frame := s.Frames[frames[0]]
+	return frame.Width, frame.Height
+}
@@ -0,0 +1,18 @@
+// File automagically generated by the "embed" tool
+// To install the tool:
+// go install https://githib.com/flevin58/embed@latest
+//
+
+package bunkers
+
+import _ "embed"
+
+
+//go:embed arch.txt
+var Arch_txt []byte
+
+//go:embed classic.txt
+var Classic_txt []byte
+
+//go:embed dome.png
+var Dome_png []byte
@@ -0,0 +1,12 @@
+// File automagically generated by the "embed" tool
+// To install the tool:
+// go install https://githib.com/flevin58/embed@latest
+//
+
+package levels
+
+import _ "embed"
+
+
+//go:embed levels.txt
+var Levels_txt []byte
@@ -0,0 +1,206 @@
+// Package audio plays the music and the sound effects of the game.
+//
+// Every sound goes through a bus: the music bus, the sound effects bus,
+// and the master bus above both. Each bus has its own volume and mute.
+// The manager decides what is heard and how loud, while a backend
+// does the actual playing, so that the game can also run without sound.
+package audio
+
+import "fmt"
+
+// Bus groups sounds that share a volume and a mute
+type Bus int
+
+const (
+	Master Bus = iota
+	Music
+	Sfx
+	busCount
+)
+
+var busNames = [busCount]string{
+	Master: "master",
+	Music:  "music",
+	Sfx:    "sfx",
+}
+
+func (b Bus) String() string {
+	if b < 0 || b >= busCount {
+		return "unknown"
+	}
+	return busNames[b]
+}
+
+// Names of the sound effects
+type SoundID string
+
+const (
+	Laser     SoundID = "laser"
+	Explosion SoundID = "explosion"
+)
+
+// Names of the music tracks
+type MusicID string
+
+const Theme MusicID = "theme"
+
+// Backend plays sounds and music on a device.
+// Sounds and music are referred to by the handle returned when loading them.
+type Backend interface {
+	// Loads a sound that can be played by several voices at the same time
+	LoadSound(data []byte, voices int) (int, error)
+	PlayVoice(sound, voice int, volume float32)
+	VoicePlaying(sound, voice int) bool
+
+	LoadMusic(data []byte) (int, error)
+	PlayMusic(music int)
+	PauseMusic(music int)
+	ResumeMusic(music int)
+	SetMusicVolume(music int, volume float32)
+	// Feeds the music stream, to be called every frame
+	UpdateMusic(music int)
+}
+
+type sound struct {
+	handle int
+	bus    Bus
+	voices int
+	// voice to try first, which is also the one that has played the longest
+	next int
+}
+
+// Manager keeps the volume of the buses and the voices of each sound
+type Manager struct {
+	backend Backend
+	volume  [busCount]float32
+	muted   [busCount]bool
+	sounds  map[SoundID]*sound
+	tracks  map[MusicID]int
+	current MusicID
+	playing bool
+}
+
+func NewManager(backend Backend) *Manager {
+	m := &Manager{
+		backend: backend,
+		sounds:  make(map[SoundID]*sound),
+		tracks:  make(map[MusicID]int),
+	}
+	for bus := range busCount {
+		m.volume[bus] = 1
+	}
+	return m
+}
+
+// Loads a sound played on the given bus. Voices is how many copies of it
+// can be heard at once: when they are all busy, the one that started first is cut off.
+func (m *Manager) LoadSound(id SoundID, bus Bus, data []byte, voices int) error {
+	voices = max(voices, 1)
+	handle, err := m.backend.LoadSound(data, voices)
+	if err != nil {
+		return fmt.Errorf("sound %s: %w", id, err)
+	}
+	m.sounds[id] = &sound{handle: handle, bus: bus, voices: voices}
+	return nil
+}
+
+func (m *Manager) LoadMusic(id MusicID, data []byte) error {
+	handle, err := m.backend.LoadMusic(data)
+	if err != nil {
+		return fmt.Errorf("music %s: %w", id, err)
+	}
+	m.tracks[id] = handle
+	return nil
+}
+
+// Plays a sound on a free voice, or on the oldest one
+func (m *Manager) Play(id SoundID) {
+	s, ok := m.sounds[id]
+	if !ok || m.Silent(s.bus) {
+		return
+	}
+	voice := s.next
+	for i := range s.voices {
+		candidate := (s.next + i) % s.voices
+		if !m.backend.VoicePlaying(s.handle, candidate) {
+			voice = candidate
+			break
+		}
+	}
+	s.next = (voice + 1) % s.voices
+	m.backend.PlayVoice(s.handle, voice, m.Level(s.bus))
+}
+
+// Starts a music track from its beginning, paused if the music is muted
+func (m *Manager) PlayMusic(id MusicID) {
+	handle, ok := m.tracks[id]
+	if !ok {
+		return
+	}
+	if m.playing {
+		m.backend.PauseMusic(m.tracks[m.current])
+	}
+	m.current = id
+	m.playing = true
+	m.backend.PlayMusic(handle)
+	m.applyMusic()
+}
+
+// Stops the current music track
+func (m *Manager) StopMusic() {
+	if m.playing {
+		m.backend.PauseMusic(m.tracks[m.current])
+		m.playing = false
+	}
+}
+
+// Keeps the current music track going
+func (m *Manager) Update() {
+	if m.playing && !m.Silent(Music) {
+		m.backend.UpdateMusic(m.tracks[m.current])
+	}
+}
+
+func (m *Manager) SetVolume(bus Bus, volume float32) {
+	m.volume[bus] = min(max(volume, 0), 1)
+	m.applyMusic()
+}
+
+func (m *Manager) Muted(bus Bus) bool {
+	return m.muted[bus]
+}
+
+func (m *Manager) SetMuted(bus Bus, muted bool) {
+	m.muted[bus] = muted
+	m.applyMusic()
+}
+
+// Volume at which the bus is heard, taking the master bus into account
+func (m *Manager) Level(bus Bus) float32 {
+	if m.Silent(bus) {
+		return 0
+	}
+	if bus == Master {
+		return m.volume[Master]
+	}
+	return m.volume[Master] * m.volume[bus]
+}
+
+// Tells if nothing on the bus can be heard
+func (m *Manager) Silent(bus Bus) bool {
+	return m.muted[bus] || m.muted[Master]
+}
+
+// The music is paused while muted, so that it resumes where it was
+func (m *Manager) applyMusic() {
+	if !m.playing {
+		return
+	}
+	handle := m.tracks[m.current]
+	m.backend.SetMusicVolume(handle, m.Level(Music))
+	if m.Silent(Music) {
+		m.backend.PauseMusic(handle)
+	} else {
+		m.backend.ResumeMusic(handle)
+	}
+}
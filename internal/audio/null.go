@@ -0,0 +1,31 @@
+package audio
+
+// Null is a backend that plays nothing.
+// It is used when there is no audio device.
+type Null struct {
+	handles int
+}
+
+func (n *Null) LoadSound(data []byte, voices int) (int, error) {
+	n.handles++
+	return n.handles, nil
+}
+
+func (n *Null) PlayVoice(sound, voice int, volume float32) {}
+
+func (n *Null) VoicePlaying(sound, voice int) bool { return false }
+
+func (n *Null) LoadMusic(data []byte) (int, error) {
+	n.handles++
+	return n.handles, nil
+}
+
+func (n *Null) PlayMusic(music int) {}
+
+func (n *Null) PauseMusic(music int) {}
+
+func (n *Null) ResumeMusic(music int) {}
+
+func (n *Null) SetMusicVolume(music int, volume float32) {}
+
+func (n *Null) UpdateMusic(music int) {}
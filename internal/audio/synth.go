@@ -0,0 +1,58 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// Sample rate of the synthesized sounds
+const sampleRate = 22050
+
+// The four descending bass notes of the fleet march, like on the arcade cabinet
+var (
+	March      = [4]SoundID{"march1", "march2", "march3", "march4"}
+	marchNotes = [4]float64{98.00, 87.31, 77.78, 73.42}
+)
+
+// Length of a march note, short enough for the fastest fleet
+const marchNoteDuration = 0.12
+
+// Returns the wav file of the given march note
+func MarchNote(note int) []byte {
+	return Tone(marchNotes[note%len(marchNotes)], marchNoteDuration)
+}
+
+// Synthesizes a square wave that fades out, as a 16 bit mono wav file
+func Tone(frequency, duration float64) []byte {
+	count := int(duration * sampleRate)
+	samples := make([]int16, count)
+	for i := range samples {
+		t := float64(i) / sampleRate
+		value := 1.0
+		if math.Mod(t*frequency, 1) >= 0.5 {
+			value = -1
+		}
+		envelope := 1 - float64(i)/float64(count)
+		samples[i] = int16(value * envelope * envelope * 0.8 * math.MaxInt16)
+	}
+
+	var buf bytes.Buffer
+	dataSize := uint32(2 * count)
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, struct {
+		Size          uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+	}{16, 1, 1, sampleRate, 2 * sampleRate, 2, 16})
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	binary.Write(&buf, binary.LittleEndian, samples)
+	return buf.Bytes()
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goinvaders/internal/game"
+	"goinvaders/internal/storage"
+	"goinvaders/internal/tools"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+)
+
+// Version of the config file format.
+// Increase it (and add a migration) when a field changes meaning.
+const CurrentVersion = 1
+
+const fileName = "config.json"
+
+// What plays as music: the music track, or the four note march
+// of the arcade cabinet that follows the speed of the fleet
+const (
+	TrackMusic = "track"
+	MarchMusic = "march"
+)
+
+// Config holds the user preferences that survive between sessions
+type Config struct {
+	Version        int     `json:"version"`
+	MasterVolume   float32 `json:"master_volume"`
+	MusicVolume    float32 `json:"music_volume"`
+	SfxVolume      float32 `json:"sfx_volume"`
+	MuteMusic      bool    `json:"mute_music"`
+	MuteSfx        bool    `json:"mute_sfx"`
+	Music          string  `json:"music"`
+	WindowWidth    int32   `json:"window_width"`
+	WindowHeight   int32   `json:"window_height"`
+	Fullscreen     bool    `json:"fullscreen"`
+	FPS            int32   `json:"fps"`
+	Difficulty     string  `json:"difficulty"`
+	SharedLives    bool    `json:"shared_lives"`
+	ReduceFlashing bool    `json:"reduce_flashing"`
+	ReduceMotion   bool    `json:"reduce_motion"`
+	// Keys of each action, by name. Actions that are not listed
+	// keep their default keys.
+	Keys map[string][]string `json:"keys,omitempty"`
+}
+
+func Default() Config {
+	return Config{
+		Version:        CurrentVersion,
+		MasterVolume:   1,
+		MusicVolume:    0.6,
+		SfxVolume:      1,
+		MuteMusic:      false,
+		MuteSfx:        false,
+		Music:          TrackMusic,
+		WindowWidth:    800,
+		WindowHeight:   800,
+		Fullscreen:     false,
+		FPS:            60,
+		Difficulty:     game.Normal.String(),
+		SharedLives:    false,
+		ReduceFlashing: false,
+		ReduceMotion:   false,
+	}
+}
+
+// Checks that all values are in their allowed range
+func (c Config) Validate() error {
+	var errs []error
+	if c.MasterVolume < 0 || c.MasterVolume > 1 {
+		errs = append(errs, fmt.Errorf("master_volume must be between 0 and 1, got %g", c.MasterVolume))
+	}
+	if c.MusicVolume < 0 || c.MusicVolume > 1 {
+		errs = append(errs, fmt.Errorf("music_volume must be between 0 and 1, got %g", c.MusicVolume))
+	}
+	if c.SfxVolume < 0 || c.SfxVolume > 1 {
+		errs = append(errs, fmt.Errorf("sfx_volume must be between 0 and 1, got %g", c.SfxVolume))
+	}
+	if c.Music != TrackMusic && c.Music != MarchMusic {
+		errs = append(errs, fmt.Errorf("music must be %q or %q, got %q", TrackMusic, MarchMusic, c.Music))
+	}
+	if c.WindowWidth < 200 || c.WindowWidth > 8192 {
+		errs = append(errs, fmt.Errorf("window_width must be between 200 and 8192, got %d", c.WindowWidth))
+	}
+	if c.WindowHeight < 200 || c.WindowHeight > 8192 {
+		errs = append(errs, fmt.Errorf("window_height must be between 200 and 8192, got %d", c.WindowHeight))
+	}
+	if c.FPS < 0 || c.FPS > 1000 {
+		errs = append(errs, fmt.Errorf("fps must be between 0 (no limit) and 1000, got %d", c.FPS))
+	}
+	if _, err := game.ParseDifficulty(c.Difficulty); err != nil {
+		errs = append(errs, fmt.Errorf("difficulty: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// Parses a config file. Fields that are not in the file keep their default
+// value, while unknown fields and invalid values are reported as errors.
+func Parse(data []byte) (Config, error) {
+	cfg := Default()
+	cfg.Version = 0
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return Config{}, describe(data, err)
+	}
+
+	switch {
+	case cfg.Version == 0:
+		return Config{}, errors.New("missing version")
+	case cfg.Version > CurrentVersion:
+		return Config{}, fmt.Errorf("version %d was written by a newer release of the game (this one reads up to version %d)", cfg.Version, CurrentVersion)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Turns json errors into messages that tell where the problem is
+func describe(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("line %d: %s", lineOf(data, syntaxErr.Offset), syntaxErr.Error())
+	case errors.As(err, &typeErr):
+		return fmt.Errorf("line %d: %s must be of type %s, got %s", lineOf(data, typeErr.Offset), typeErr.Field, typeErr.Type, typeErr.Value)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return fmt.Errorf("unknown setting %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return err
+}
+
+func lineOf(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// Loads the config file from the config folder.
+// If the file does not exist yet, the default config is saved and returned.
+// When the config folder cannot be used, the game still starts with the
+// default config: the problem is only logged.
+func Load() (Config, error) {
+	path, err := tools.GetConfigPath(fileName)
+	if err != nil {
+		log.Printf("Using the default config: %s", err)
+		return Default(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg := Default()
+		if err := cfg.Save(); err != nil {
+			log.Printf("Could not save the default config: %s", err)
+		}
+		return cfg, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c Config) Save() error {
+	path, err := tools.GetConfigPath(fileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteFile(path, append(data, '\n'))
+}
+
+// Changes the config file on disk.
+// Only the saved file is affected: command line overrides are never written back.
+func Update(change func(cfg *Config)) error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	change(&cfg)
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return cfg.Save()
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"goinvaders/internal/assets/levels"
+	"goinvaders/internal/game"
+	"goinvaders/internal/tools"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+)
+
+// Folder of the config folder where the user can add bunker shapes
+const shapesDir = "bunkers"
+
+// Loads the levels from the config folder if the user put a level file
+// there, or else the levels built into the game.
+// Either way they can use the bunker shapes of the user.
+// Only a level file of the user that cannot be read or parsed is an error.
+func LoadLevels() (game.LevelSet, error) {
+	shapes, err := LoadShapes()
+	if err != nil {
+		return game.LevelSet{}, err
+	}
+
+	path, err := tools.GetConfigPath(game.LevelsFile)
+	if err != nil {
+		log.Printf("Using the built in levels: %s", err)
+		return game.ParseLevels(game.LevelsFile, levels.Levels_txt, shapes)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return game.ParseLevels(game.LevelsFile, levels.Levels_txt, shapes)
+	}
+	if err != nil {
+		return game.LevelSet{}, err
+	}
+	return game.ParseLevels(path, data, shapes)
+}
+
+// Returns the built in bunker shapes, together with the text and png files
+// found in the bunkers folder. A user shape replaces a built in shape
+// with the same name. When the config folder cannot be used, only the
+// built in shapes are returned.
+func LoadShapes() (game.Shapes, error) {
+	shapes := maps.Clone(game.DefaultShapes)
+
+	dir, err := tools.GetConfigPath(shapesDir)
+	if err != nil {
+		log.Printf("Using the built in bunker shapes: %s", err)
+		return shapes, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return shapes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !game.IsShapeFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		shape, err := game.ParseShape(path, data)
+		if err != nil {
+			return nil, err
+		}
+		shapes[game.ShapeName(path)] = shape
+	}
+	return shapes, nil
+}
@@ -0,0 +1,162 @@
+package effects
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+type Color struct {
+	R, G, B, A uint8
+}
+
+type Particle struct {
+	X, Y   float32
+	VX, VY float32
+	Age    float32
+	Life   float32
+	Size   float32
+	Color  Color
+	// copied from the preset, so that presets can be changed on the fly
+	gravity float32
+	drag    float32
+}
+
+// Fraction of its life the particle has left, from 1 down to 0.
+// Useful to fade particles out.
+func (p *Particle) Remaining() float32 {
+	return 1 - p.Age/p.Life
+}
+
+// Preset describes how the particles of an effect look and move
+type Preset struct {
+	// Number of particles emitted at once
+	Count int
+	// Range of initial speeds, in pixels per second
+	MinSpeed, MaxSpeed float32
+	// Range of lifetimes, in seconds
+	MinLife, MaxLife float32
+	// Range of sizes, in pixels
+	MinSize, MaxSize float32
+	// Direction of the particles in radians (0 is right, Pi/2 is down)
+	// and how far from it they can go. A spread of Pi sends them all around.
+	Direction, Spread float32
+	// Downward acceleration, in pixels per second squared
+	Gravity float32
+	// Fraction of the speed lost every second
+	Drag float32
+	// Each particle takes one of these colors at random
+	Colors []Color
+}
+
+// Returns a copy of the preset going in another direction
+func (p Preset) Towards(direction float32) Preset {
+	p.Direction = direction
+	return p
+}
+
+var (
+	yellow = Color{R: 243, G: 216, B: 63, A: 255}
+	white  = Color{R: 255, G: 255, B: 255, A: 255}
+	orange = Color{R: 240, G: 120, B: 30, A: 255}
+	red    = Color{R: 163, G: 22, B: 3, A: 255}
+)
+
+// The presets of the game effects
+var (
+	AlienDeath = Preset{
+		Count: 24, MinSpeed: 60, MaxSpeed: 220, MinLife: 0.25, MaxLife: 0.6,
+		MinSize: 2, MaxSize: 4, Spread: math.Pi, Drag: 2,
+		Colors: []Color{yellow, white},
+	}
+	MysteryShipDeath = Preset{
+		Count: 60, MinSpeed: 80, MaxSpeed: 320, MinLife: 0.4, MaxLife: 1.0,
+		MinSize: 2, MaxSize: 5, Spread: math.Pi, Drag: 1.5,
+		Colors: []Color{red, orange, yellow, white},
+	}
+	BunkerDebris = Preset{
+		Count: 8, MinSpeed: 40, MaxSpeed: 140, MinLife: 0.2, MaxLife: 0.5,
+		MinSize: 2, MaxSize: 3, Spread: 0.8, Gravity: 400,
+		Colors: []Color{yellow},
+	}
+	PlayerDeath = Preset{
+		Count: 90, MinSpeed: 50, MaxSpeed: 280, MinLife: 0.6, MaxLife: 1.4,
+		MinSize: 2, MaxSize: 6, Direction: -math.Pi / 2, Spread: math.Pi, Gravity: 150, Drag: 1,
+		Colors: []Color{white, yellow, orange, red},
+	}
+)
+
+// System keeps all the live particles in a pool allocated once:
+// the first count particles are alive, and a dead particle is replaced
+// by the last live one, so emitting and updating never allocate.
+// When the pool is full new particles are dropped.
+type System struct {
+	particles []Particle
+	count     int
+	rand      *rand.Rand
+}
+
+// Creates a system able to hold capacity particles at once.
+// The effects have their own random source, so that they do not change
+// the random numbers the game logic gets.
+func NewSystem(capacity int, seed uint64) *System {
+	s := &System{particles: make([]Particle, capacity)}
+	s.Reset(seed)
+	return s
+}
+
+// Removes all the particles and restarts the random source
+func (s *System) Reset(seed uint64) {
+	s.count = 0
+	s.rand = rand.New(rand.NewPCG(seed, ^seed))
+}
+
+func (s *System) between(min, max float32) float32 {
+	return min + (max-min)*s.rand.Float32()
+}
+
+// Emits the particles of an effect from the given point
+func (s *System) Emit(preset *Preset, x, y float32) {
+	for range preset.Count {
+		if s.count == len(s.particles) {
+			return
+		}
+		angle := float64(preset.Direction + s.between(-preset.Spread, preset.Spread))
+		speed := s.between(preset.MinSpeed, preset.MaxSpeed)
+		s.particles[s.count] = Particle{
+			X:       x,
+			Y:       y,
+			VX:      speed * float32(math.Cos(angle)),
+			VY:      speed * float32(math.Sin(angle)),
+			Life:    s.between(preset.MinLife, preset.MaxLife),
+			Size:    s.between(preset.MinSize, preset.MaxSize),
+			Color:   preset.Colors[s.rand.IntN(len(preset.Colors))],
+			gravity: preset.Gravity,
+			drag:    preset.Drag,
+		}
+		s.count++
+	}
+}
+
+// Moves all the particles dt seconds forward and removes the dead ones
+func (s *System) Update(dt float32) {
+	for i := 0; i < s.count; {
+		p := &s.particles[i]
+		p.Age += dt
+		if p.Age >= p.Life {
+			s.count--
+			s.particles[i] = s.particles[s.count]
+			continue
+		}
+		drag := max(1-p.drag*dt, 0)
+		p.VX *= drag
+		p.VY = p.VY*drag + p.gravity*dt
+		p.X += p.VX * dt
+		p.Y += p.VY * dt
+		i++
+	}
+}
+
+// The live particles. The slice is only valid until the next update.
+func (s *System) Particles() []Particle {
+	return s.particles[:s.count]
+}
@@ -1,48 +1,81 @@
 package game
 
-import (
-	"goinvaders/internal/assets"
-
-	rl "github.com/gen2brain/raylib-go/raylib"
-)
+import "goinvaders/internal/assets/atlas"
 
 type Alien struct {
 	alienType int32
-	position  rl.Vector2
-	image     rl.Texture2D
+	position  Vector2
+	previous  Vector2
+	size      Vector2
 	active    bool
+	// step of the fleet march, which gives the frame to draw
+	step int
 }
 
 func NewAlien(alienType int32, xpos int32, ypos int32) *Alien {
+	position := Vector2{X: float32(xpos), Y: float32(ypos)}
 	return &Alien{
 		alienType: alienType,
-		position:  rl.Vector2{X: float32(xpos), Y: float32(ypos)},
-		image:     assets.GetAlienImage(alienType),
+		position:  position,
+		previous:  position,
+		size:      spriteSize(atlas.AlienSprite(alienType)),
 		active:    true,
 	}
 }
 
-func (a *Alien) GetRect() rl.Rectangle {
-	return rl.Rectangle{
+func (a *Alien) Type() int32 {
+	return a.alienType
+}
+
+// Position to draw the alien at, alpha being the fraction of the current tick
+func (a *Alien) InterpolatedPosition(alpha float32) Vector2 {
+	return Lerp(a.previous, a.position, alpha)
+}
+
+func (a *Alien) savePosition() {
+	a.previous = a.position
+}
+
+func (a *Alien) GetRect() Rectangle {
+	return Rectangle{
 		X:      a.position.X,
 		Y:      a.position.Y,
-		Width:  float32(a.image.Width),
-		Height: float32(a.image.Height),
+		Width:  a.size.X,
+		Height: a.size.Y,
 	}
 }
 
-func (a *Alien) CollidedWith(other Collideable) bool {
-	return rl.CheckCollisionRecs(other.GetRect(), a.GetRect())
+// Points earned for destroying the mystery ship
+const MysteryShipScore int32 = 500
+
+// Points earned for destroying an alien of the given type
+func AlienScore(alienType int32) int32 {
+	return alienType * 100
 }
 
 func (a *Alien) GetScore() int32 {
-	return a.alienType * 100
+	return AlienScore(a.alienType)
+}
+
+// Moves the alien sideways by dx pixels
+func (a *Alien) Move(dx float32) {
+	a.position.X += dx
+}
+
+func (a *Alien) Alive() bool {
+	return a.active
+}
+
+func (a *Alien) Layer() Layer {
+	return AlienLayer
 }
 
-func (a *Alien) Update(direction int32) {
-	a.position.X += float32(direction)
+// The aliens do not move by themselves, the fleet moves them as a whole
+func (a *Alien) Update(ctx *Context) {
+	a.step = ctx.Game.fleetStep
 }
 
-func (a *Alien) Draw() {
-	rl.DrawTextureV(a.image, a.position, rl.White)
+// Aliens change frame at every step of the fleet
+func (a *Alien) Draw(r Renderer) {
+	r.DrawSprite(atlas.AlienSprite(a.alienType), a.step, a.InterpolatedPosition(r.Alpha()), white)
 }
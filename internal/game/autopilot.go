@@ -0,0 +1,56 @@
+package game
+
+// How far ahead the autopilot looks for alien lasers, in pixels
+const dangerDistance float32 = 180
+
+// Autopilot plays the game by itself, for the demo of the attract mode.
+// It dodges the alien lasers coming its way, otherwise it goes under
+// the lowest alien nearest to it and fires.
+func Autopilot(g *Game) Input {
+	ship := g.ships[0].GetRect()
+	center := ship.X + ship.Width/2
+
+	for laser := range All[*Laser](g.world) {
+		if laser.ship != nil {
+			continue
+		}
+		rect := laser.GetRect()
+		above := rect.Y < ship.Y && ship.Y-rect.Y < dangerDistance
+		inLine := rect.X+rect.Width > ship.X-10 && rect.X < ship.X+ship.Width+10
+		if above && inLine {
+			// run to the side with more room
+			if rect.X+rect.Width/2 > center && ship.X > 50 || ship.X+ship.Width > g.width-50 {
+				return Input{Left: true}
+			}
+			return Input{Right: true}
+		}
+	}
+
+	var target *Alien
+	for alien := range All[*Alien](g.world) {
+		if target == nil || alien.position.Y > target.position.Y ||
+			alien.position.Y == target.position.Y && abs(alien.position.X-center) < abs(target.position.X-center) {
+			target = alien
+		}
+	}
+	if target == nil {
+		return Input{}
+	}
+
+	rect := target.GetRect()
+	dx := rect.X + rect.Width/2 - center
+	switch {
+	case dx < -rect.Width/4:
+		return Input{Left: true}
+	case dx > rect.Width/4:
+		return Input{Right: true}
+	}
+	return Input{Fire: true}
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,97 @@
+package game
+
+// DeathTiming tells how long each part of the death of the spaceship lasts,
+// in seconds
+type DeathTiming struct {
+	// Time from the hit to the spaceship coming back, with the fleet
+	// standing still. The game is over at the end of it if no lives are left.
+	RespawnDelay float64
+	// Time the spaceship cannot be hit after coming back
+	Invulnerability float64
+	// Time the spaceship is shown, then hidden, while it blinks
+	BlinkInterval float64
+}
+
+// Blows up the spaceship. When no other spaceship is left flying,
+// the world freezes until it respawns.
+func (g *Game) KillSpaceship(ship *Spaceship) {
+	rect := ship.GetRect()
+	timing := g.params.Death
+
+	player := g.shipPlayer(ship)
+	lives := g.livesOf(player)
+	*lives = max(*lives-1, 0)
+	ship.alive = false
+	g.events.Publish(PlayerHit{Player: player, Lives: *lives, Bounds: rect})
+	// its own lasers are gone, and those of the aliens too when no other
+	// spaceship keeps playing while it explodes
+	frozen := !g.anyShipAlive()
+	for laser := range All[*Laser](g.world) {
+		if laser.ship == ship || laser.ship == nil && frozen {
+			laser.active = false
+		}
+	}
+	ship.respawnTime = g.time + timing.RespawnDelay
+}
+
+// The fleet reached the spaceships: the game of the players is over
+func (g *Game) Invade() {
+	for _, ship := range g.ships {
+		*g.livesOf(g.shipPlayer(ship)) = 0
+		if ship.alive {
+			g.KillSpaceship(ship)
+		}
+	}
+}
+
+// Brings back the spaceships whose explosion is over
+func (g *Game) respawnShips() {
+	for _, ship := range g.ships {
+		if !ship.alive && !ship.out && g.time >= ship.respawnTime {
+			g.RespawnSpaceship(ship)
+		}
+	}
+}
+
+// Brings the spaceship back, or hands the game to the next player.
+// A spaceship whose player has no lives left is out, and the game
+// is over when all the spaceships are out.
+func (g *Game) RespawnSpaceship(ship *Spaceship) {
+	if g.mode == TwoPlayers {
+		if next := g.nextPlayer(); next != g.current {
+			g.switchTurn(next)
+			return
+		}
+	}
+	if *g.livesOf(g.shipPlayer(ship)) == 0 {
+		ship.out = true
+		for _, other := range g.ships {
+			if !other.out {
+				return
+			}
+		}
+		g.GameOver()
+		return
+	}
+	g.resetShip(ship)
+	ship.invulnerableUntil = g.time + g.params.Death.Invulnerability
+}
+
+func (g *Game) anyShipAlive() bool {
+	for _, ship := range g.ships {
+		if ship.alive {
+			return true
+		}
+	}
+	return false
+}
+
+// Puts the spaceship back at its starting place. In co-op games
+// the spaceships start side by side.
+func (g *Game) resetShip(ship *Spaceship) {
+	ship.Reset(g.width, g.height)
+	if count := len(g.ships); count > 1 {
+		ship.position.X = g.width*float32(ship.player+1)/float32(count+1) - ship.size.X/2
+		ship.previous = ship.position
+	}
+}
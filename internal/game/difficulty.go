@@ -0,0 +1,186 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Curve gives a value for each level: Start at level 1, then changing by
+// Step at every new level, without going past Limit.
+type Curve struct {
+	Start float64
+	Step  float64
+	Limit float64
+}
+
+func (c Curve) At(level int32) float64 {
+	value := c.Start + c.Step*float64(max(level-1, 0))
+	switch {
+	case c.Step > 0:
+		return math.Min(value, c.Limit)
+	case c.Step < 0:
+		return math.Max(value, c.Limit)
+	}
+	return c.Start
+}
+
+// The levels where the curve may change course: the first one, and those
+// on either side of the point where it reaches its limit.
+// In between, the curve is a straight line.
+func (c Curve) turns() []int32 {
+	levels := []int32{1}
+	if c.Step != 0 {
+		reached := min(1+(c.Limit-c.Start)/c.Step, math.MaxInt32)
+		levels = append(levels, int32(math.Floor(reached)), int32(math.Ceil(reached)))
+	}
+	return levels
+}
+
+// Finds the first level where the curve goes above the other one.
+// Both curves being straight lines between their turns, comparing them
+// at the turns is the same as comparing them at every level.
+func (c Curve) above(other Curve) (int32, bool) {
+	levels := append(c.turns(), other.turns()...)
+	slices.Sort(levels)
+	for _, level := range levels {
+		if c.At(level) > other.At(level) {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// SpeedUp tells how much faster the fleet marches as it shrinks
+type SpeedUp struct {
+	// Speed multiplier with the whole fleet and with a single alien left
+	Full float64
+	Last float64
+	// Shape of the curve: 1 is linear, higher values keep the fleet
+	// slow for longer and make the last aliens much faster
+	Exponent float64
+}
+
+// Returns the speed multiplier with remaining aliens out of total
+func (s SpeedUp) Factor(remaining, total int) float64 {
+	if total <= 1 || remaining >= total {
+		return s.Full
+	}
+	destroyed := float64(total-remaining) / float64(total-1)
+	return s.Full + (s.Last-s.Full)*math.Pow(destroyed, s.Exponent)
+}
+
+// Progression describes how the game gets harder from one level to the next
+type Progression struct {
+	// Y position of the top row of the fleet
+	StartY Curve
+	// Speed of the full alien fleet, in pixels per second
+	AlienSpeed Curve
+	// How the fleet speeds up as aliens are destroyed
+	SpeedUp SpeedUp
+	// Pixels the fleet moves down when it reaches a side of the screen
+	Drop Curve
+	// Seconds between two alien lasers
+	FireInterval Curve
+	// Speed of the alien lasers, in pixels per second
+	LaserSpeed Curve
+	// Range of seconds between two appearances of the mystery ship
+	MysteryMinInterval Curve
+	MysteryMaxInterval Curve
+	// Timing of the death of the spaceship
+	Death DeathTiming
+}
+
+// LevelParams are the values of the progression and the layout of a single level
+type LevelParams struct {
+	StartY             float32
+	AlienSpeed         float32
+	SpeedUp            SpeedUp
+	Drop               float32
+	FireInterval       float64
+	LaserSpeed         float32
+	MysteryMinInterval int32
+	MysteryMaxInterval int32
+	Death              DeathTiming
+	Layout             Layout
+}
+
+func (p Progression) Level(level int32) LevelParams {
+	mysteryMin := int32(math.Round(p.MysteryMinInterval.At(level)))
+	mysteryMax := int32(math.Round(p.MysteryMaxInterval.At(level)))
+	return LevelParams{
+		StartY:             float32(p.StartY.At(level)),
+		AlienSpeed:         float32(p.AlienSpeed.At(level)),
+		SpeedUp:            p.SpeedUp,
+		Drop:               float32(p.Drop.At(level)),
+		FireInterval:       p.FireInterval.At(level),
+		LaserSpeed:         float32(p.LaserSpeed.At(level)),
+		MysteryMinInterval: mysteryMin,
+		MysteryMaxInterval: max(mysteryMax, mysteryMin),
+		Death:              p.Death,
+	}
+}
+
+// Difficulty adapts every level to the skill of the player
+type Difficulty uint8
+
+const (
+	Normal Difficulty = iota
+	Easy
+	Hard
+)
+
+var difficultyNames = map[Difficulty]string{
+	Easy:   "easy",
+	Normal: "normal",
+	Hard:   "hard",
+}
+
+// All the difficulties, from the easiest
+func Difficulties() []Difficulty {
+	return []Difficulty{Easy, Normal, Hard}
+}
+
+func (d Difficulty) String() string {
+	if name, ok := difficultyNames[d]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func ParseDifficulty(name string) (Difficulty, error) {
+	for d, n := range difficultyNames {
+		if n == name {
+			return d, nil
+		}
+	}
+	return Normal, fmt.Errorf("unknown difficulty %q, expected easy, normal or hard", name)
+}
+
+// How each difficulty changes the levels
+type difficultyScale struct {
+	alienSpeed   float32
+	fireInterval float64
+	laserSpeed   float32
+	lives        int32
+}
+
+var difficultyScales = map[Difficulty]difficultyScale{
+	Easy:   {alienSpeed: 0.8, fireInterval: 1.5, laserSpeed: 0.85, lives: 5},
+	Normal: {alienSpeed: 1, fireInterval: 1, laserSpeed: 1, lives: 3},
+	Hard:   {alienSpeed: 1.2, fireInterval: 0.75, laserSpeed: 1.15, lives: 3},
+}
+
+// Returns the level parameters changed for the difficulty
+func (d Difficulty) Apply(params LevelParams) LevelParams {
+	scale := difficultyScales[d]
+	params.AlienSpeed *= scale.alienSpeed
+	params.FireInterval *= scale.fireInterval
+	params.LaserSpeed *= scale.laserSpeed
+	return params
+}
+
+// Lives at the start of a game
+func (d Difficulty) Lives() int32 {
+	return difficultyScales[d].lives
+}
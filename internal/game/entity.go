@@ -0,0 +1,172 @@
+package game
+
+import (
+	"goinvaders/internal/effects"
+	"iter"
+)
+
+// Layer tells when an entity is drawn: lower layers first
+type Layer int
+
+const (
+	BunkerLayer Layer = iota
+	AlienLayer
+	ShipLayer
+	LaserLayer
+	EffectLayer
+	layerCount
+)
+
+// Context is what an entity gets to see of the game when it updates
+type Context struct {
+	Game *Game
+	// While the spaceships explode the world stands still,
+	// only the effects go on
+	Frozen bool
+}
+
+// Renderer draws the entities. It is implemented by the presentation layer,
+// so that the simulation does not depend on raylib.
+type Renderer interface {
+	// Fraction of the current tick, to draw moving entities between
+	// their last two positions
+	Alpha() float32
+	// Draws the frame of the animation of the sprite sheet for the given
+	// step, starting over after the last frame
+	DrawSprite(animation string, step int, position Vector2, tint effects.Color)
+	DrawRectangle(rect Rectangle, color effects.Color)
+	// Entities that blink are see through instead
+	ReduceFlashing() bool
+}
+
+// Entity is anything living in the world
+type Entity interface {
+	// The rectangle the entity takes up in the world
+	GetRect() Rectangle
+	// Advances the entity by one tick
+	Update(ctx *Context)
+	Draw(r Renderer)
+	// Entities that are no longer alive are removed from the world
+	Alive() bool
+	Layer() Layer
+}
+
+// Entities that move remember where they were at the start of the tick,
+// so that they can be drawn between two ticks
+type mover interface {
+	savePosition()
+}
+
+// World holds the entities of the game. It updates them in the order
+// they were added, draws them layer by layer and removes the dead ones.
+type World struct {
+	entities []Entity
+	// entities added while updating, they join after the update
+	pending  []Entity
+	updating bool
+}
+
+func NewWorld() *World {
+	return &World{
+		entities: make([]Entity, 0),
+		pending:  make([]Entity, 0),
+	}
+}
+
+// Adds the entity to the world. Entities added by another entity
+// while the world updates are first updated on the next tick.
+func (w *World) Add(entity Entity) {
+	if w.updating {
+		w.pending = append(w.pending, entity)
+		return
+	}
+	w.entities = append(w.entities, entity)
+}
+
+// Removes all the entities
+func (w *World) Clear() {
+	clear(w.entities)
+	w.entities = w.entities[:0]
+	clear(w.pending)
+	w.pending = w.pending[:0]
+}
+
+// Updates all the entities, then removes those that died
+func (w *World) Update(ctx *Context) {
+	w.updating = true
+	for _, entity := range w.entities {
+		entity.Update(ctx)
+	}
+	w.updating = false
+	w.removeDead()
+
+	w.entities = append(w.entities, w.pending...)
+	clear(w.pending)
+	w.pending = w.pending[:0]
+}
+
+// Removes the entities that died, in place and keeping their order
+func (w *World) removeDead() {
+	alive := w.entities[:0]
+	for _, entity := range w.entities {
+		if entity.Alive() {
+			alive = append(alive, entity)
+		}
+	}
+	clear(w.entities[len(alive):])
+	w.entities = alive
+}
+
+// Draws the living entities, layer by layer. Within a layer
+// they are drawn in the order they were added.
+func (w *World) Draw(r Renderer) {
+	for layer := range layerCount {
+		for _, entity := range w.entities {
+			if entity.Layer() == layer && entity.Alive() {
+				entity.Draw(r)
+			}
+		}
+	}
+}
+
+func (w *World) savePositions() {
+	for _, entity := range w.entities {
+		if m, ok := entity.(mover); ok {
+			m.savePosition()
+		}
+	}
+}
+
+// Iterates over the living entities of type T, in the order they were added
+func All[T Entity](w *World) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, entity := range w.entities {
+			if e, ok := entity.(T); ok && entity.Alive() {
+				if !yield(e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Counts the living entities of type T
+func Count[T Entity](w *World) int {
+	count := 0
+	for range All[T](w) {
+		count++
+	}
+	return count
+}
+
+// The living entity of type T at index, in the order they were added
+func nth[T Entity](entities iter.Seq[T], index int) T {
+	var zero T
+	for entity := range entities {
+		if index == 0 {
+			return entity
+		}
+		index--
+	}
+	return zero
+}
@@ -0,0 +1,134 @@
+package game
+
+import "reflect"
+
+// Event is something that happened in the game. Events are published on
+// the Bus of the game as they happen, in the middle of the tick.
+type Event interface {
+	event()
+}
+
+// A game started, after Start or Restart
+type GameStarted struct {
+	Mode Mode
+	Seed uint64
+}
+
+// A spaceship fired a laser
+type LaserFired struct {
+	Player int
+}
+
+// The fleet made a step of its march
+type FleetStepped struct {
+	// steps marched since the level started, they come faster
+	// as the fleet speeds up
+	Step int
+}
+
+// The laser of a player destroyed an alien
+type AlienKilled struct {
+	Player    int
+	AlienType int32
+	Points    int32
+	// where the alien was
+	Bounds Rectangle
+}
+
+// The laser of a player destroyed the mystery ship
+type MysteryShipKilled struct {
+	Player int
+	Points int32
+	Bounds Rectangle
+}
+
+// A spaceship was destroyed, by an alien laser or by the fleet landing
+type PlayerHit struct {
+	Player int
+	// lives left to the player, the spaceship being destroyed is not counted
+	Lives  int32
+	Bounds Rectangle
+}
+
+// What destroyed part of a bunker
+type DamageSource int
+
+const (
+	ShipLaser DamageSource = iota
+	AlienLaser
+	// the fleet going through the bunker
+	AlienBody
+)
+
+// Some blocks of a bunker were destroyed
+type BunkerDamaged struct {
+	Source DamageSource
+	// the laser or the alien that hit the bunker
+	Bounds Rectangle
+}
+
+// All the aliens of the level were destroyed
+type LevelCleared struct {
+	Player int
+	Level  int32
+}
+
+// The game is over for all the players, it is then in the GameOver state
+type GameEnded struct {
+	Level int32
+}
+
+func (GameStarted) event()       {}
+func (LaserFired) event()        {}
+func (FleetStepped) event()      {}
+func (AlienKilled) event()       {}
+func (MysteryShipKilled) event() {}
+func (PlayerHit) event()         {}
+func (BunkerDamaged) event()     {}
+func (LevelCleared) event()      {}
+func (GameEnded) event()         {}
+
+// Bus hands the events to the handlers subscribed to them, right away and
+// in the order they subscribed, whether they watch one type of event or all
+// of them. The game subscribes first, for the score and the effects. The other
+// handlers only watch: if they changed the course of the game, replays would
+// no longer play the same.
+type Bus struct {
+	handlers []handler
+}
+
+type handler struct {
+	// the type of event handled, nil for every event
+	kind   reflect.Type
+	handle func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make([]handler, 0),
+	}
+}
+
+// Calls handle with every event of type E
+func Subscribe[E Event](bus *Bus, handle func(E)) {
+	bus.handlers = append(bus.handlers, handler{
+		kind: reflect.TypeFor[E](),
+		handle: func(event Event) {
+			handle(event.(E))
+		},
+	})
+}
+
+// Calls handle with every event, whatever its type
+func (b *Bus) SubscribeAll(handle func(Event)) {
+	b.handlers = append(b.handlers, handler{handle: handle})
+}
+
+func (b *Bus) Publish(event Event) {
+	kind := reflect.TypeOf(event)
+	for _, h := range b.handlers {
+		if h.kind == nil || h.kind == kind {
+			h.handle(event)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package game
+
+import "goinvaders/internal/assets/atlas"
+
+// How long an explosion animation lasts, in seconds.
+// Longer explosions play it again until they are over.
+const ExplosionDuration float64 = 0.3
+
+// Explosion marks the place where something was destroyed.
+// It does not take part in the game, it is only there to be drawn.
+type Explosion struct {
+	position  Vector2
+	size      Vector2
+	startTime float64
+	duration  float64
+	// seconds since the explosion started, as of the last update
+	age float64
+}
+
+// Creates an explosion centered on the destroyed entity,
+// lasting duration seconds
+func NewExplosion(rect Rectangle, now, duration float64) *Explosion {
+	size := spriteSize(atlas.ExplosionSprite)
+	return &Explosion{
+		position: Vector2{
+			X: rect.X + (rect.Width-size.X)/2,
+			Y: rect.Y + (rect.Height-size.Y)/2,
+		},
+		size:      size,
+		startTime: now,
+		duration:  duration,
+	}
+}
+
+// Seconds since the explosion started
+func (e *Explosion) Age(now float64) float64 {
+	return now - e.startTime
+}
+
+func (e *Explosion) GetRect() Rectangle {
+	return Rectangle{X: e.position.X, Y: e.position.Y, Width: e.size.X, Height: e.size.Y}
+}
+
+func (e *Explosion) Alive() bool {
+	return e.age < e.duration
+}
+
+func (e *Explosion) Layer() Layer {
+	return EffectLayer
+}
+
+func (e *Explosion) Update(ctx *Context) {
+	e.age = e.Age(ctx.Game.time)
+}
+
+// Explosions play all their frames once, whatever their number
+func (e *Explosion) Draw(r Renderer) {
+	frames := spriteFrames(atlas.ExplosionSprite)
+	r.DrawSprite(atlas.ExplosionSprite, int(e.age*float64(frames)/ExplosionDuration), e.position, white)
+}
@@ -1,20 +1,28 @@
 package game
 
 import (
-	"goinvaders/internal/assets"
-	"goinvaders/internal/assets/fonts"
-	"goinvaders/internal/assets/sounds"
-	"goinvaders/internal/tools"
-	"image/color"
+	"goinvaders/internal/effects"
+	"math"
+	"math/rand/v2"
+)
 
-	rl "github.com/gen2brain/raylib-go/raylib"
+// The simulation always advances in fixed steps, whatever the frame rate.
+// Speeds are expressed in pixels per second and converted with perTick.
+const (
+	TicksPerSecond         = 60
+	TickDuration   float64 = 1.0 / TicksPerSecond
 )
 
-type Collideable interface {
-	GetRect() rl.Rectangle
+func perTick(speed float32) float32 {
+	return speed / TicksPerSecond
 }
 
-const alienLaserShootInterval float64 = 0.35
+// Distance the fleet travels between two steps of its march.
+// The aliens change animation frame at every step.
+const fleetStepDistance float32 = 16
+
+// Most particles alive at once
+const maxParticles = 2048
 
 type GameState int
 
@@ -27,416 +35,604 @@ const (
 	Quit
 )
 
-var (
-	grey   = color.RGBA{R: 29, G: 29, B: 27, A: 255}
-	yellow = color.RGBA{R: 243, G: 216, B: 63, A: 255}
-	green  = color.RGBA{R: 11, G: 102, B: 35, A: 255}
-	red    = color.RGBA{R: 163, G: 22, B: 3, A: 255}
-)
+// Input is the state of the controls of a spaceship for a single update
+type Input struct {
+	Left  bool
+	Right bool
+	Fire  bool
+}
+
+// Most spaceships on screen at the same time, in co-op games
+const MaxShips = 2
 
+// The input of every spaceship for a single update
+type TickInput [MaxShips]Input
+
+// Game is the pure simulation: it owns the world dimensions, the game clock
+// and the random source, and never talks to raylib.
+//
+// The entities live in the world. Only the spaceships are also kept aside:
+// one that is out of the game leaves the world but its player keeps it,
+// and the input goes to the spaceships by their index.
 type Game struct {
-	spaceship          Spaceship
-	mysteryship        MysteryShip
-	obstacles          []*Obstacle
-	aliens             []*Alien
+	width              float32
+	height             float32
+	time               float64
+	seed               uint64
+	rand               *rand.Rand
+	events             *Bus
+	world              *World
+	context            Context
+	ships              []*Spaceship
 	aliensDirection    int32
-	alienLasers        []*Laser
+	fleetSize          int
+	fleetStep          int
+	fleetTravel        float32
+	effects            *effects.System
 	timeLastAlienFired float64
 	msSpawnInterval    float64
 	msTimeLastSpawned  float64
-	lives              int32
-	font               rl.Font
+	levels             LevelSet
+	difficulty         Difficulty
+	nextDifficulty     Difficulty
+	mode               Mode
+	nextMode           Mode
+	players            []Player
+	current            int
+	turnUntil          float64
+	params             LevelParams
 	level              int32
-	score              int32
 	highScore          int32
-	music              rl.Music
-	explosionSound     rl.Sound
-	mutesfx            bool
-	mutemusic          bool
 	state              GameState
 }
 
-func New() Game {
-	game := Game{
-		spaceship:      NewSpaceship(),
-		mysteryship:    NewMysteryShip(),
-		font:           assets.LoadFont(fonts.Monogram_ttf),
-		music:          assets.LoadMusic(sounds.Music_ogg),
-		explosionSound: assets.LoadSound(sounds.Explosion_ogg),
-		mutesfx:        false,
-		mutemusic:      false,
+// Creates a new game whose randomness is driven by seed.
+// Use NewSeed to get a different game each time.
+// The game stays Idle until Start is called.
+func New(width, height int32, seed uint64) *Game {
+	game := &Game{
+		width:   float32(width),
+		height:  float32(height),
+		seed:    seed,
+		events:  NewBus(),
+		levels:  DefaultLevels,
+		world:   NewWorld(),
+		effects: effects.NewSystem(maxParticles, seed),
 	}
+	game.context.Game = game
+	game.subscribeScoring()
+	game.subscribeEffects()
 
-	game.InitGame()
-	if !rl.IsMusicReady(game.music) {
-		rl.TraceLog(rl.LogError, "Music not ready")
-	}
-	rl.PlayMusicStream(game.music)
-	rl.SetMusicVolume(game.music, 0.6)
+	game.Reset(seed)
 	return game
 }
 
+// The events of the game, to subscribe to them
+func (g *Game) Events() *Bus {
+	return g.events
+}
+
+// Changes the difficulty, used from the next game on
+func (g *Game) SetDifficulty(difficulty Difficulty) {
+	g.nextDifficulty = difficulty
+}
+
+func (g *Game) Difficulty() Difficulty {
+	return g.difficulty
+}
+
+// Changes the levels of the game and sets it up again
+func (g *Game) SetLevels(levels LevelSet) {
+	g.levels = levels
+	g.Reset(g.seed)
+}
+
+func (g *Game) SetHighScore(highScore int32) {
+	g.highScore = highScore
+}
+
+func (g *Game) Width() float32 {
+	return g.width
+}
+
+func (g *Game) Height() float32 {
+	return g.height
+}
+
+func (g *Game) Time() float64 {
+	return g.time
+}
+
+// The seed of the current game, needed to reproduce it
+func (g *Game) Seed() uint64 {
+	return g.seed
+}
+
+func (g *Game) State() GameState {
+	return g.state
+}
+
+// Lives of the current player
+func (g *Game) Lives() int32 {
+	return *g.livesOf(g.current)
+}
+
+func (g *Game) Level() int32 {
+	return g.level
+}
+
+// Score of the current player
+func (g *Game) Score() int32 {
+	return g.players[g.current].score
+}
+
+func (g *Game) HighScore() int32 {
+	return g.highScore
+}
+
+// The entities of the game, to draw them
+func (g *Game) World() *World {
+	return g.world
+}
+
+func (g *Game) Particles() []effects.Particle {
+	return g.effects.Particles()
+}
+
+// The parameters of the current level
+func (g *Game) Params() LevelParams {
+	return g.params
+}
+
 func (g *Game) InitLevel() {
 	g.level++
+	g.params = g.difficulty.Apply(g.levels.Params(g.level))
+	g.CreateObstacles()
+	g.CreateAliens()
 	g.aliensDirection = 1
-	g.msSpawnInterval = float64(rl.GetRandomValue(10, 20))
-	g.msTimeLastSpawned = 0
-	g.timeLastAlienFired = 0
+	g.fleetStep = 0
+	g.fleetTravel = 0
+	g.msSpawnInterval = g.mysterySpawnInterval()
+	g.msTimeLastSpawned = g.time
+	g.timeLastAlienFired = g.time
 	g.state = Running
 }
 
 func (g *Game) InitGame() {
-	g.lives = 3
-	g.level = 0
-	g.score = 0
-	g.highScore = 0
-	g.LoadHighScore()
-	g.ResetGame()
-	g.InitLevel()
+	g.rand = newRandom(g.seed)
+	g.effects.Reset(g.seed)
+	g.time = 0
+	g.difficulty = g.nextDifficulty
+	g.mode = g.nextMode
+	g.initPlayers()
 }
 
 func (g *Game) ResetGame() {
-	g.spaceship.Reset()
-	g.aliens = make([]*Alien, 0)
-	g.alienLasers = make([]*Laser, 0)
-	g.obstacles = make([]*Obstacle, 0)
-	g.CreateObstacles()
-	g.CreateAliens()
+	g.world.Clear()
+	for _, ship := range g.ships {
+		g.resetShip(ship)
+		g.world.Add(ship)
+	}
 }
 
 func (g *Game) CreateObstacles() {
-	obstacleWidth := GetObstacleWidth()
-	gap := (rl.GetScreenWidth() - (4 * obstacleWidth)) / 5
-	for i := range 4 {
+	layout := g.params.Layout
+	for _, bunker := range layout.Bunkers {
+		g.world.Add(NewObstacle(bunker.Position.X, bunker.Position.Y, bunker.Shape, bunker.BlockSize))
+	}
+	if len(layout.Bunkers) > 0 {
+		return
+	}
+
+	count := layout.BunkerCount
+	obstacleWidth := int(float32(layout.BunkerShape.Width()) * layout.BlockSize)
+	gap := (int(g.width) - (count * obstacleWidth)) / (count + 1)
+	for i := range count {
 		offsetx := (i+1)*gap + i*obstacleWidth
-		g.obstacles = append(g.obstacles, NewObstacle(float32(offsetx), float32(rl.GetScreenHeight()-200)))
+		g.world.Add(NewObstacle(float32(offsetx), g.height-200, layout.BunkerShape, layout.BlockSize))
 	}
 }
 
 func (g *Game) CreateAliens() {
-	for row := range 5 {
-		var alienType int32
-		switch {
-		case row == 0:
-			alienType = 3
-		case row == 1 || row == 2:
-			alienType = 2
-		default:
-			alienType = 1
-		}
-		for col := range 11 {
-			posx := 75 + col*55
-			posy := 110 + row*55
-			g.aliens = append(g.aliens, NewAlien(alienType, int32(posx), int32(posy)))
+	g.fleetSize = 0
+	formation := g.params.Layout.Formation
+	for row, types := range formation.Rows {
+		for col, alienType := range types {
+			if alienType == 0 {
+				continue
+			}
+			posx := formation.Left + float32(col)*formation.Spacing
+			posy := g.params.StartY + float32(row)*formation.Spacing
+			g.world.Add(NewAlien(alienType, int32(posx), int32(posy)))
+			g.fleetSize++
 		}
 	}
 }
 
-func (g *Game) MoveDownAliens(distance int) {
-	for _, alien := range g.aliens {
-		alien.position.Y += float32(distance)
+func (g *Game) mysterySpawnInterval() float64 {
+	return float64(g.randomValue(g.params.MysteryMinInterval, g.params.MysteryMaxInterval))
+}
+
+func (g *Game) MoveDownAliens(distance float32) {
+	for alien := range All[*Alien](g.world) {
+		alien.position.Y += distance
 	}
 }
 
+// Speed of the fleet in pixels per second: the fewer the aliens, the faster they go
+func (g *Game) FleetSpeed() float32 {
+	factor := g.params.SpeedUp.Factor(Count[*Alien](g.world), g.fleetSize)
+	return g.params.AlienSpeed * float32(factor)
+}
+
+// Moves the fleet sideways as a whole. When the step would take it past
+// a side of the screen it stops at the side, then drops down once and
+// turns around, so that higher speeds never skip or repeat a drop.
 func (g *Game) MoveAliens() {
-	for _, alien := range g.aliens {
-		if alien.position.X+float32(alien.image.Width) > float32(rl.GetScreenWidth())-25 {
-			g.aliensDirection = -1
-			g.MoveDownAliens(4)
-		}
-		if alien.position.X < 25 {
-			g.aliensDirection = 1
-			g.MoveDownAliens(4)
-		}
-		alien.Update(g.aliensDirection)
+	if Count[*Alien](g.world) == 0 {
+		return
+	}
+
+	left, right := g.width, float32(0)
+	for alien := range All[*Alien](g.world) {
+		left = min(left, alien.position.X)
+		right = max(right, alien.position.X+alien.size.X)
 	}
+
+	dx := float32(g.aliensDirection) * perTick(g.FleetSpeed())
+	turn := false
+	if g.aliensDirection > 0 && right+dx >= g.width-25 {
+		dx = max(g.width-25-right, 0)
+		turn = true
+	}
+	if g.aliensDirection < 0 && left+dx <= 25 {
+		dx = min(25-left, 0)
+		turn = true
+	}
+
+	for alien := range All[*Alien](g.world) {
+		alien.Move(dx)
+	}
+	g.fleetTravel += max(dx, -dx)
+	if g.fleetTravel >= fleetStepDistance {
+		g.fleetTravel -= fleetStepDistance
+		g.StepFleet()
+	}
+	if turn {
+		g.aliensDirection = -g.aliensDirection
+		g.MoveDownAliens(g.params.Drop)
+		g.fleetTravel = 0
+		g.StepFleet()
+	}
+}
+
+// Every step of the fleet plays a note of its march,
+// so the march speeds up with the fleet
+func (g *Game) StepFleet() {
+	g.fleetStep++
+	g.events.Publish(FleetStepped{Step: g.fleetStep})
 }
 
 func (g *Game) AliensShootLaser() {
 
 	// there must be an alien
-	if len(g.aliens) <= 0 {
+	count := Count[*Alien](g.world)
+	if count <= 0 {
 		return
 	}
 
 	// enough time should have passed from last alien laser
-	if rl.GetTime()-g.timeLastAlienFired < alienLaserShootInterval {
+	if g.time-g.timeLastAlienFired < g.params.FireInterval {
 		return
 	}
 
 	// create a random alien laser and add it to the queue
-	randomIndex := rl.GetRandomValue(0, int32(len(g.aliens)-1))
-	alien := g.aliens[randomIndex]
-	laserx := int32(alien.position.X) + alien.image.Width/2
-	lasery := int32(alien.position.Y) + alien.image.Height
-	g.alienLasers = append(g.alienLasers, NewLaser(laserx, lasery, 6))
-	g.timeLastAlienFired = rl.GetTime()
-}
-
-func (g *Game) AddScore(earned int32) {
-	g.score += earned
-	if g.score > g.highScore {
-		g.highScore = g.score
+	randomIndex := g.randomValue(0, int32(count-1))
+	alien := nth(All[*Alien](g.world), int(randomIndex))
+	laserx := int32(alien.position.X) + int32(alien.size.X)/2
+	lasery := int32(alien.position.Y) + int32(alien.size.Y)
+	g.world.Add(NewLaser(laserx, lasery, g.params.LaserSpeed))
+	g.timeLastAlienFired = g.time
+}
+
+// Emits an effect from the center of rect
+func (g *Game) emit(preset effects.Preset, rect Rectangle) {
+	g.effects.Emit(&preset, rect.X+rect.Width/2, rect.Y+rect.Height/2)
+}
+
+// The score follows the aliens destroyed
+func (g *Game) subscribeScoring() {
+	Subscribe(g.events, func(e AlienKilled) {
+		g.AddScore(e.Player, e.Points)
+	})
+	Subscribe(g.events, func(e MysteryShipKilled) {
+		g.AddScore(e.Player, e.Points)
+	})
+}
+
+// Explosions and particles where things are destroyed
+func (g *Game) subscribeEffects() {
+	Subscribe(g.events, func(e AlienKilled) {
+		g.world.Add(NewExplosion(e.Bounds, g.time, ExplosionDuration))
+		g.emit(effects.AlienDeath, e.Bounds)
+	})
+	Subscribe(g.events, func(e MysteryShipKilled) {
+		g.world.Add(NewExplosion(e.Bounds, g.time, ExplosionDuration))
+		g.emit(effects.MysteryShipDeath, e.Bounds)
+	})
+	Subscribe(g.events, func(e PlayerHit) {
+		g.world.Add(NewExplosion(e.Bounds, g.time, g.params.Death.RespawnDelay))
+		g.emit(effects.PlayerDeath, e.Bounds)
+	})
+	Subscribe(g.events, func(e BunkerDamaged) {
+		// the debris fly back towards where the laser came from
+		switch e.Source {
+		case ShipLaser:
+			g.emit(effects.BunkerDebris.Towards(math.Pi/2), e.Bounds)
+		case AlienLaser:
+			g.emit(effects.BunkerDebris.Towards(-math.Pi/2), e.Bounds)
+		}
+	})
+}
+
+// Credits the player with the points
+func (g *Game) AddScore(player int, earned int32) {
+	p := &g.players[player]
+	p.score += earned
+	if p.score > g.highScore {
+		g.highScore = p.score
 	}
 }
 
+// Collisions are found in two phases: a cheap test against the bounds of
+// the whole fleet and of each bunker, then an exact test against the few
+// entities that can be hit. Nothing is allocated on the way, and what is
+// destroyed is removed in place at the end.
 func (g *Game) CheckForCollisions() {
-	// Spaceship lasers
-	for _, laser := range g.spaceship.lasers {
-		// Check against aliens
-		deleteAliens := false
-		for _, alien := range g.aliens {
-			if laser.CollidedWith(alien) {
-				if !g.mutesfx {
-					rl.PlaySound(g.explosionSound)
-				}
-				g.AddScore(alien.GetScore())
-				alien.active = false
+	fleet := g.fleetBounds()
+	for _, ship := range g.ships {
+		g.CheckLaserCollisions(ship, fleet)
+	}
+
+	// Alien Lasers
+	for laser := range All[*Laser](g.world) {
+		if laser.ship != nil || !laser.active {
+			continue
+		}
+		rect := laser.GetRect()
+		// Alien lasers against Spaceships
+		for _, ship := range g.ships {
+			if ship.alive && !ship.Invulnerable(g.time) && CheckCollisionRecs(rect, ship.GetRect()) {
 				laser.active = false
-				deleteAliens = true
+				g.KillSpaceship(ship)
+				break
 			}
 		}
-		// If we deactivated some aliens, delete them
-		if deleteAliens {
-			g.aliens = tools.FilterSlice(g.aliens,
-				func(alien *Alien) bool {
-					return alien.active
-				})
+		if !laser.active {
+			continue
 		}
-		// If now there are no more aliens, we won this level!
-		if len(g.aliens) == 0 {
-			g.state = LevelUp
+		// Alien lasers against Obstacles
+		for obstacle := range All[*Obstacle](g.world) {
+			if obstacle.Hit(rect) {
+				g.events.Publish(BunkerDamaged{Source: AlienLaser, Bounds: rect})
+				laser.active = false
+				break
+			}
 		}
+	}
 
-		// Check against blocks
-		for _, obstacle := range g.obstacles {
-			deleteBlocks := false
-			for _, block := range obstacle.blocks {
-				if laser.CollidedWith(block) {
-					block.active = false
-					laser.active = false
-					deleteBlocks = true
-				}
-			}
-			if deleteBlocks {
-				obstacle.blocks = tools.FilterSlice(obstacle.blocks,
-					func(block *Block) bool {
-						return block.active
-					})
+	for alien := range All[*Alien](g.world) {
+		rect := alien.GetRect()
+		// Alien against obstacles
+		for obstacle := range All[*Obstacle](g.world) {
+			if obstacle.Hit(rect) {
+				g.events.Publish(BunkerDamaged{Source: AlienBody, Bounds: rect})
 			}
 		}
-
-		// Check against mystery ship
-		if laser.CollidedWith(&g.mysteryship) {
-			if !g.mutesfx {
-				rl.PlaySound(g.explosionSound)
+		// Alien against Spaceships
+		for _, ship := range g.ships {
+			if ship.alive && CheckCollisionRecs(rect, ship.GetRect()) {
+				g.Invade()
 			}
-			g.AddScore(500)
-			g.mysteryship.alive = false
-			laser.active = false
 		}
 	}
+}
 
-	// Alien Lasers
-	for _, laser := range g.alienLasers {
-		// Alien lasers against Spaceship
-		if laser.CollidedWith(&g.spaceship) {
-			laser.active = false
-			g.lives--
-			// TBD: spaceship explosion (sound and/or animation)
-			if g.lives == 0 {
-				g.GameOver()
-			}
-			rl.TraceLog(rl.LogInfo, "Spaceship hit")
+// The lasers of a spaceship score for the player who flies it.
+// A laser stops at the first thing it hits.
+func (g *Game) CheckLaserCollisions(ship *Spaceship, fleet Rectangle) {
+	player := g.shipPlayer(ship)
+	for laser := range All[*Laser](g.world) {
+		if laser.ship != ship || !laser.active {
+			continue
 		}
-		// Alien lasers against Obstacles
-		for _, obstacle := range g.obstacles {
-			deleteBlocks := false
-			for _, block := range obstacle.blocks {
-				if laser.CollidedWith(block) {
-					block.active = false
+		rect := laser.GetRect()
+
+		// Check against aliens
+		if CheckCollisionRecs(rect, fleet) {
+			for alien := range All[*Alien](g.world) {
+				if alien.active && CheckCollisionRecs(rect, alien.GetRect()) {
+					alien.active = false
+					g.events.Publish(AlienKilled{
+						Player:    player,
+						AlienType: alien.alienType,
+						Points:    alien.GetScore(),
+						Bounds:    alien.GetRect(),
+					})
 					laser.active = false
-					deleteBlocks = true
+					break
 				}
 			}
-			if deleteBlocks {
-				obstacle.blocks = tools.FilterSlice(obstacle.blocks,
-					func(block *Block) bool {
-						return block.active
-					})
-			}
 		}
-	}
+		if !laser.active {
+			continue
+		}
 
-	for _, alien := range g.aliens {
-		// Alien against obstacles
-		for _, obstacle := range g.obstacles {
-			deleteBlocks := false
-			for _, block := range obstacle.blocks {
-				if alien.CollidedWith(block) {
-					block.active = false
-					deleteBlocks = true
-				}
-			}
-			if deleteBlocks {
-				obstacle.blocks = tools.FilterSlice(obstacle.blocks,
-					func(block *Block) bool {
-						return block.active
-					})
+		// Check against blocks
+		for obstacle := range All[*Obstacle](g.world) {
+			if obstacle.Hit(rect) {
+				g.events.Publish(BunkerDamaged{Source: ShipLaser, Bounds: rect})
+				laser.active = false
+				break
 			}
 		}
-		// Alien against Spaceship
-		if alien.CollidedWith(&g.spaceship) {
-			g.GameOver()
+		if !laser.active {
+			continue
 		}
-	}
-}
 
-func (g *Game) Update() {
-	if g.state != Running {
-		return
+		// Check against mystery ships, which can only be hit while they fly
+		for mysteryship := range All[*MysteryShip](g.world) {
+			if mysteryship.alive && CheckCollisionRecs(rect, mysteryship.GetRect()) {
+				g.events.Publish(MysteryShipKilled{
+					Player: player,
+					Points: MysteryShipScore,
+					Bounds: mysteryship.GetRect(),
+				})
+				mysteryship.alive = false
+				laser.active = false
+				break
+			}
+		}
 	}
 
-	rl.UpdateMusicStream(g.music)
-
-	g.CheckForCollisions()
-
-	if rl.GetTime()-g.msTimeLastSpawned > g.msSpawnInterval {
-		g.mysteryship.Spawn()
-		g.msTimeLastSpawned = rl.GetTime()
-		g.msSpawnInterval = float64(rl.GetRandomValue(10, 20))
+	// The world removes the aliens destroyed. If there are none left, we won this level!
+	if Count[*Alien](g.world) == 0 && g.state == Running {
+		g.state = LevelUp
+		g.events.Publish(LevelCleared{Player: g.current, Level: g.level})
 	}
-	g.spaceship.Update()
-	g.mysteryship.Update()
-	g.MoveAliens()
-
-	// delete inactive lasers
-	g.alienLasers = tools.FilterSlice(g.alienLasers,
-		func(laser *Laser) bool {
-			return laser.active
-		})
+}
 
-	g.AliensShootLaser()
-	for _, laser := range g.alienLasers {
-		laser.Update()
+// The smallest rectangle holding all the aliens
+func (g *Game) fleetBounds() Rectangle {
+	var bounds Rectangle
+	first := true
+	for alien := range All[*Alien](g.world) {
+		if first {
+			bounds = alien.GetRect()
+			first = false
+			continue
+		}
+		bounds = bounds.Union(alien.GetRect())
 	}
+	return bounds
 }
 
-func (g *Game) Draw() {
-	rl.ClearBackground(grey)
-
-	// Draw the GUI
-	rl.DrawRectangleRoundedLines(rl.Rectangle{X: 10, Y: 10, Width: 780, Height: 780}, 0.18, 20, 2, yellow)
-	rl.DrawLineEx(rl.Vector2{X: 25, Y: 730}, rl.Vector2{X: 775, Y: 730}, 3, yellow)
-	if g.state == GameOver {
-		g.TextAt(570, 740, "GAME OVER")
-	} else {
-		g.TextAt(570, 740, "LEVEL %02d", g.level)
+// Sends a mystery ship from a side of the world.
+// There is only one at a time: a new one replaces the one flying.
+func (g *Game) SpawnMysteryShip(fromLeft bool) {
+	for mysteryship := range All[*MysteryShip](g.world) {
+		mysteryship.alive = false
 	}
-	for i := range g.lives {
-		g.spaceship.DrawAt(50*(i+1), 745)
-	}
-	g.TextAt(50, 15, "SCORE")
-	g.TextAt(50, 40, "%05d", g.score)
-
-	g.TextAt(570, 15, "HIGH SCORE")
-	g.TextAt(570, 40, "%05d", g.highScore)
-
-	g.spaceship.Draw()
-	g.mysteryship.Draw()
+	g.world.Add(NewMysteryShip(fromLeft, g.width))
+}
 
-	for _, obstacle := range g.obstacles {
-		obstacle.Draw()
+// Advances the simulation by one tick (TickDuration seconds) using the input
+// of each spaceship, in order. Spaceships without input stand still.
+func (g *Game) Update(inputs ...Input) {
+	if g.state != Running {
+		return
 	}
 
-	for _, alien := range g.aliens {
-		alien.Draw()
+	g.time += TickDuration
+	// remember where the entities were at the start of the tick,
+	// so that the presentation layer can interpolate between two ticks
+	g.world.savePositions()
+
+	// While all the spaceships explode the rest of the world stands still,
+	// and also while a player's turn starts
+	frozen := !g.anyShipAlive()
+	g.respawnShips()
+	if frozen || g.TurnStarting() {
+		g.UpdateWorld(true)
+		return
 	}
 
-	for _, laser := range g.alienLasers {
-		laser.Draw()
+	// Handle movement and laser fire
+	for i, ship := range g.ships {
+		if !ship.alive || i >= len(inputs) {
+			continue
+		}
+		input := inputs[i]
+		if input.Left {
+			ship.MoveLeft()
+		} else if input.Right {
+			ship.MoveRight(g.width)
+		} else if input.Fire {
+			if laser := ship.FireLaser(g.time); laser != nil {
+				g.world.Add(laser)
+				g.events.Publish(LaserFired{Player: g.shipPlayer(ship)})
+			}
+		}
 	}
 
-	if g.state == GameOver {
-		g.GameOverDraw()
-	}
+	g.CheckForCollisions()
 
-	if g.state == LevelUp {
-		g.LevelUpDraw()
+	if g.time-g.msTimeLastSpawned > g.msSpawnInterval {
+		g.SpawnMysteryShip(g.randomValue(0, 1) == 0)
+		g.msTimeLastSpawned = g.time
+		g.msSpawnInterval = g.mysterySpawnInterval()
 	}
-}
+	g.MoveAliens()
+	g.AliensShootLaser()
 
-func (g *Game) ShouldQuit() bool {
-	return g.state == Quit || rl.WindowShouldClose()
+	g.UpdateWorld(false)
 }
 
-func (g *Game) HandleGameOverInput() {
-	if rl.IsKeyPressed(rl.KeyEscape) {
-		g.state = Quit
-	}
-	if rl.IsKeyPressed(rl.KeyEnter) {
-		g.ResetGame()
-		g.InitGame()
-	}
+// Updates the entities and the particles, removing those that are over.
+// The explosions and the particles keep going even when the world is frozen.
+func (g *Game) UpdateWorld(frozen bool) {
+	g.context.Frozen = frozen
+	g.world.Update(&g.context)
+	g.effects.Update(float32(TickDuration))
 }
 
-func (g *Game) HandleLevelUpInput() {
-	if rl.IsKeyPressed(rl.KeyEnter) {
-		g.ResetGame()
-		g.InitLevel()
+func (g *Game) TogglePause() {
+	switch g.state {
+	case Running:
+		g.state = Paused
+	case Paused:
+		g.state = Running
 	}
 }
 
-func (g *Game) HandleInput() {
-	if g.state == GameOver {
-		g.HandleGameOverInput()
-		return
-	}
+// Sets up a new game with the given seed. It stays Idle, with the first
+// level on screen, until Start is called.
+func (g *Game) Reset(seed uint64) {
+	g.seed = seed
+	g.InitGame()
+	g.state = Idle
+}
 
-	if g.state == LevelUp {
-		g.HandleLevelUpInput()
-		return
+func (g *Game) Start() {
+	if g.state == Idle {
+		g.state = Running
+		g.events.Publish(GameStarted{Mode: g.mode, Seed: g.seed})
 	}
+}
 
-	// Handle movement and laser fire
-	if g.state == Running {
-		if rl.IsKeyDown(rl.KeyLeft) {
-			g.spaceship.MoveLeft()
-		} else if rl.IsKeyDown(rl.KeyRight) {
-			g.spaceship.MoveRight()
-		} else if rl.IsKeyDown(rl.KeySpace) {
-			g.spaceship.FireLaser()
-		}
-	}
+// Seed for the game after this one. It is drawn from the random source,
+// so a whole session is still reproducible from the first seed.
+func (g *Game) NextSeed() uint64 {
+	return g.rand.Uint64()
+}
 
-	// Handle pause / resume
-	if rl.IsKeyPressed(rl.KeyP) {
-		if g.state == Paused {
-			g.state = Running
-		} else {
-			g.state = Paused
-		}
-	}
+// Starts a brand new game after a game over
+func (g *Game) Restart() {
+	g.Reset(g.NextSeed())
+	g.Start()
+}
 
-	// Handle pause/Resume music
-	if rl.IsKeyPressed(rl.KeyM) {
-		g.mutemusic = !g.mutemusic
-		if g.mutemusic {
-			rl.PauseMusicStream(g.music)
-		} else {
-			rl.ResumeMusicStream(g.music)
-		}
-	}
+// Starts the next level after a level up
+func (g *Game) NextLevel() {
+	g.ResetGame()
+	g.InitLevel()
+}
 
-	// Handle pause/Resume sfx
-	if rl.IsKeyPressed(rl.KeyS) {
-		g.mutesfx = !g.mutesfx
-		g.spaceship.mute = g.mutesfx
-	}
+func (g *Game) Quit() {
+	g.state = Quit
 }
 
 func (g *Game) GameOver() {
 	g.state = GameOver
-	g.SaveHighScore()
-	rl.TraceLog(rl.LogInfo, "Game Over!")
+	g.events.Publish(GameEnded{Level: g.level})
 }
@@ -0,0 +1,172 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// A level with a single alien right above the spaceship, no bunkers and no
+// mystery ship. The fleet barely moves.
+const duelLevels = `
+[defaults]
+start_y         = 400
+alien_speed     = 1
+speed_up        = 1 1 1
+drop            = 20
+fire_interval   = %g
+laser_speed     = 400
+mystery_min     = 1000
+mystery_max     = 1000
+respawn_delay   = 0.5
+invulnerability = 0
+blink_interval  = 0.1
+
+[level]
+left    = 380
+bunkers = 0
+row     = 1
+`
+
+func duel(t *testing.T, fireInterval float64) *Game {
+	t.Helper()
+	levels, err := ParseLevels("duel.txt", []byte(fmt.Sprintf(duelLevels, fireInterval)), DefaultShapes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(WorldWidth, WorldHeight, 1)
+	g.SetLevels(levels)
+	g.Reset(1)
+	return g
+}
+
+// The events, without the fleet steps and the bounds, which are not
+// what the tests are about
+func record(g *Game) *[]Event {
+	events := make([]Event, 0)
+	g.Events().SubscribeAll(func(event Event) {
+		switch e := event.(type) {
+		case FleetStepped:
+			return
+		case AlienKilled:
+			e.Bounds = Rectangle{}
+			event = e
+		case PlayerHit:
+			e.Bounds = Rectangle{}
+			event = e
+		}
+		events = append(events, event)
+	})
+	return &events
+}
+
+// Starts the game and plays it with the same input until it stops running,
+// for a minute at most
+func play(g *Game, input Input) {
+	g.Start()
+	for tick := 0; tick < 60*TicksPerSecond && g.State() == Running; tick++ {
+		g.Update(input)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		fireInterval float64
+		input        Input
+		want         []Event
+	}{
+		{
+			name:         "the spaceship shoots the last alien",
+			fireInterval: 1000,
+			input:        Input{Fire: true},
+			want: []Event{
+				GameStarted{Mode: OnePlayer, Seed: 1},
+				LaserFired{Player: 0},
+				LaserFired{Player: 0},
+				AlienKilled{Player: 0, AlienType: 1, Points: AlienScore(1)},
+				LevelCleared{Player: 0, Level: 1},
+			},
+		},
+		{
+			name:         "the alien shoots the spaceship until the game is over",
+			fireInterval: 0.2,
+			want: []Event{
+				GameStarted{Mode: OnePlayer, Seed: 1},
+				PlayerHit{Player: 0, Lives: 2},
+				PlayerHit{Player: 0, Lives: 1},
+				PlayerHit{Player: 0, Lives: 0},
+				GameEnded{Level: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := duel(t, tt.fireInterval)
+			events := record(g)
+			play(g, tt.input)
+			if !slices.Equal(*events, tt.want) {
+				t.Errorf("got events %+v, want %+v", *events, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoring(t *testing.T) {
+	g := duel(t, 1000)
+	play(g, Input{Fire: true})
+	if g.State() != LevelUp {
+		t.Fatalf("got state %v, want %v", g.State(), LevelUp)
+	}
+	if g.Score() != AlienScore(1) {
+		t.Errorf("got score %d, want %d", g.Score(), AlienScore(1))
+	}
+
+	g.NextLevel()
+	if g.Level() != 2 || Count[*Alien](g.World()) != 1 {
+		t.Errorf("got level %d with %d aliens, want level 2 with 1 alien", g.Level(), Count[*Alien](g.World()))
+	}
+}
+
+func TestKillSpaceship(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  Mode
+		kill  []int
+		alien bool
+		// lasers left to each spaceship
+		ships []bool
+	}{
+		{name: "one player", mode: OnePlayer, kill: []int{0}, alien: false, ships: []bool{false}},
+		{name: "co-op partner still playing", mode: CoOp, kill: []int{0}, alien: true, ships: []bool{false, true}},
+		{name: "co-op both hit", mode: CoOp, kill: []int{0, 1}, alien: false, ships: []bool{false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := duel(t, 1000)
+			g.SetMode(tt.mode)
+			g.Reset(1)
+			g.Start()
+
+			alienLaser := NewLaser(100, 100, 100)
+			g.world.Add(alienLaser)
+			shipLasers := make([]*Laser, len(g.ships))
+			for i, ship := range g.ships {
+				shipLasers[i] = ship.FireLaser(1)
+				g.world.Add(shipLasers[i])
+			}
+			for _, ship := range tt.kill {
+				g.KillSpaceship(g.ships[ship])
+			}
+
+			if alienLaser.Alive() != tt.alien {
+				t.Errorf("alien laser alive: got %v, want %v", alienLaser.Alive(), tt.alien)
+			}
+			for i, laser := range shipLasers {
+				if laser.Alive() != tt.ships[i] {
+					t.Errorf("laser of spaceship %d alive: got %v, want %v", i, laser.Alive(), tt.ships[i])
+				}
+			}
+		})
+	}
+}
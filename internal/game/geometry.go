@@ -0,0 +1,39 @@
+package game
+
+// Vector2 and Rectangle mirror the raylib types with the same name.
+// The simulation uses its own copies so that it can run without a window.
+type Vector2 struct {
+	X float32
+	Y float32
+}
+
+type Rectangle struct {
+	X      float32
+	Y      float32
+	Width  float32
+	Height float32
+}
+
+// Returns the point between from (alpha = 0) and to (alpha = 1).
+// It is used to draw moving entities between two simulation ticks.
+func Lerp(from, to Vector2, alpha float32) Vector2 {
+	return Vector2{
+		X: from.X + (to.X-from.X)*alpha,
+		Y: from.Y + (to.Y-from.Y)*alpha,
+	}
+}
+
+// The smallest rectangle holding both rectangles
+func (r Rectangle) Union(other Rectangle) Rectangle {
+	left := min(r.X, other.X)
+	top := min(r.Y, other.Y)
+	right := max(r.X+r.Width, other.X+other.Width)
+	bottom := max(r.Y+r.Height, other.Y+other.Height)
+	return Rectangle{X: left, Y: top, Width: right - left, Height: bottom - top}
+}
+
+// Same algorithm as rl.CheckCollisionRecs
+func CheckCollisionRecs(rec1, rec2 Rectangle) bool {
+	return rec1.X < rec2.X+rec2.Width && rec1.X+rec1.Width > rec2.X &&
+		rec1.Y < rec2.Y+rec2.Height && rec1.Y+rec1.Height > rec2.Y
+}
@@ -1,27 +1,38 @@
 package game
 
-import (
-	"image/color"
-
-	rl "github.com/gen2brain/raylib-go/raylib"
-)
+// Speed of the spaceship lasers in pixels per second
+const laserSpeed float32 = 360
 
 type Laser struct {
-	position rl.Vector2
+	position Vector2
+	previous Vector2
 	speed    float32
 	active   bool
+	// the spaceship that fired the laser, nil for the aliens
+	ship *Spaceship
 }
 
+// speed is in pixels per second, positive going down
 func NewLaser(posx, posy int32, speed float32) *Laser {
+	position := Vector2{X: float32(posx), Y: float32(posy)}
 	return &Laser{
-		position: rl.Vector2{X: float32(posx), Y: float32(posy)},
+		position: position,
+		previous: position,
 		speed:    speed,
 		active:   true,
 	}
 }
 
-func (l *Laser) GetRect() rl.Rectangle {
-	return rl.Rectangle{
+func (l *Laser) InterpolatedPosition(alpha float32) Vector2 {
+	return Lerp(l.previous, l.position, alpha)
+}
+
+func (l *Laser) savePosition() {
+	l.previous = l.position
+}
+
+func (l *Laser) GetRect() Rectangle {
+	return Rectangle{
 		X:      l.position.X,
 		Y:      l.position.Y,
 		Width:  4,
@@ -29,25 +40,24 @@ func (l *Laser) GetRect() rl.Rectangle {
 	}
 }
 
-func (l *Laser) CollidedWith(other Collideable) bool {
-	return rl.CheckCollisionRecs(other.GetRect(), l.GetRect())
+func (l *Laser) Alive() bool {
+	return l.active
 }
 
-func (l *Laser) IsActive() bool {
-	return l.active
+func (l *Laser) Layer() Layer {
+	return LaserLayer
 }
 
-func (l *Laser) Update() {
-	if l.active {
-		l.position.Y += l.speed
-		if (l.position.Y > float32(rl.GetScreenHeight())-100) || (l.position.Y < 25) {
+func (l *Laser) Update(ctx *Context) {
+	if l.active && !ctx.Frozen {
+		l.position.Y += perTick(l.speed)
+		if (l.position.Y > ctx.Game.height-100) || (l.position.Y < 25) {
 			l.active = false
 		}
 	}
 }
 
-func (l *Laser) Draw() {
-	if l.active {
-		rl.DrawRectangle(int32(l.position.X), int32(l.position.Y), 4, 15, color.RGBA{R: 243, G: 216, B: 63, A: 255})
-	}
+func (l *Laser) Draw(r Renderer) {
+	pos := l.InterpolatedPosition(r.Alpha())
+	r.DrawRectangle(Rectangle{X: pos.X, Y: pos.Y, Width: 4, Height: 15}, yellow)
 }
@@ -0,0 +1,536 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"goinvaders/internal/assets/atlas"
+	"goinvaders/internal/assets/levels"
+	"hash/crc32"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Name of the level file, both built in and in the config folder
+const LevelsFile = "levels.txt"
+
+// Size of the world the levels are made for
+const (
+	WorldWidth  = 800
+	WorldHeight = 800
+)
+
+// The levels built into the game
+var DefaultLevels = MustParseLevels(LevelsFile, levels.Levels_txt, DefaultShapes)
+
+// Formation is the layout of the alien fleet at the start of a level
+type Formation struct {
+	// Alien type of each place, row by row from the top, 0 for no alien
+	Rows [][]int32
+	// X position of the first column
+	Left float32
+	// Distance between two rows and between two columns
+	Spacing float32
+}
+
+// Bunker tells where and how to build a bunker
+type Bunker struct {
+	// Top left corner
+	Position  Vector2
+	Shape     Shape
+	BlockSize float32
+	// line of the level file, to report a bunker that does not fit
+	line int
+}
+
+// Layout is what a level looks like when it starts
+type Layout struct {
+	Formation Formation
+	// Bunkers placed one by one. When empty, BunkerCount bunkers
+	// are spread evenly across the screen.
+	Bunkers     []Bunker
+	BunkerCount int
+	// Shape and block size of the bunkers, unless a bunker has its own
+	BunkerShape Shape
+	BlockSize   float32
+}
+
+// Level describes a single level of a level file
+type Level struct {
+	Progression Progression
+	Layout      Layout
+}
+
+// LevelSet is the sequence of levels of a game.
+// Once past the last level, the last level is played again and again,
+// getting harder each time.
+type LevelSet struct {
+	Levels []Level
+	// Identifies the level file: replays can only be played
+	// with the levels they were recorded with
+	Checksum uint32
+}
+
+// Returns the parameters of the given level, starting from 1
+func (s LevelSet) Params(level int32) LevelParams {
+	l := s.Levels[min(int(max(level, 1)), len(s.Levels))-1]
+	params := l.Progression.Level(level)
+	params.Layout = l.Layout
+	return params
+}
+
+func MustParseLevels(name string, data []byte, shapes Shapes) LevelSet {
+	set, err := ParseLevels(name, data, shapes)
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// A setting of the difficulty progression, which can be given
+// in [defaults] or for a single level
+type progressionSetting func(p *Progression, values []float64) error
+
+func curveSetting(field func(p *Progression) *Curve) progressionSetting {
+	return func(p *Progression, values []float64) error {
+		var curve Curve
+		switch len(values) {
+		case 1:
+			curve = Curve{Start: values[0], Step: 0, Limit: values[0]}
+		case 3:
+			curve = Curve{Start: values[0], Step: values[1], Limit: values[2]}
+		default:
+			return errors.New("expected a number, or start step limit")
+		}
+		if curve.Start <= 0 || curve.Limit <= 0 {
+			return errors.New("values must be more than 0")
+		}
+		if (curve.Step > 0 && curve.Limit < curve.Start) || (curve.Step < 0 && curve.Limit > curve.Start) {
+			return errors.New("the limit is never reached with this step")
+		}
+		if curve.Step == 0 && curve.Limit != curve.Start {
+			return errors.New("with a step of 0 the limit must be the start")
+		}
+		*field(p) = curve
+		return nil
+	}
+}
+
+func timeSetting(field func(p *Progression) *float64) progressionSetting {
+	return func(p *Progression, values []float64) error {
+		if len(values) != 1 {
+			return errors.New("expected a number")
+		}
+		if values[0] < 0 {
+			return errors.New("must not be negative")
+		}
+		*field(p) = values[0]
+		return nil
+	}
+}
+
+var progressionSettings = map[string]progressionSetting{
+	"start_y":       curveSetting(func(p *Progression) *Curve { return &p.StartY }),
+	"alien_speed":   curveSetting(func(p *Progression) *Curve { return &p.AlienSpeed }),
+	"drop":          curveSetting(func(p *Progression) *Curve { return &p.Drop }),
+	"fire_interval": curveSetting(func(p *Progression) *Curve { return &p.FireInterval }),
+	"laser_speed":   curveSetting(func(p *Progression) *Curve { return &p.LaserSpeed }),
+	"mystery_min":   curveSetting(func(p *Progression) *Curve { return &p.MysteryMinInterval }),
+	"mystery_max":   curveSetting(func(p *Progression) *Curve { return &p.MysteryMaxInterval }),
+	"speed_up": func(p *Progression, values []float64) error {
+		if len(values) != 3 {
+			return errors.New("expected full fleet multiplier, last alien multiplier and exponent")
+		}
+		for _, value := range values {
+			if value <= 0 {
+				return errors.New("values must be more than 0")
+			}
+		}
+		p.SpeedUp = SpeedUp{Full: values[0], Last: values[1], Exponent: values[2]}
+		return nil
+	},
+	"respawn_delay":   timeSetting(func(p *Progression) *float64 { return &p.Death.RespawnDelay }),
+	"invulnerability": timeSetting(func(p *Progression) *float64 { return &p.Death.Invulnerability }),
+	"blink_interval": func(p *Progression, values []float64) error {
+		if len(values) != 1 || values[0] <= 0 {
+			return errors.New("expected a number more than 0")
+		}
+		p.Death.BlinkInterval = values[0]
+		return nil
+	},
+}
+
+// Shape of the bunkers when a level does not tell
+const defaultShape = "classic"
+
+// Settings that can be given more than once in a level
+var repeatedSettings = map[string]bool{"row": true, "bunker": true}
+
+type levelParser struct {
+	name     string
+	shapes   Shapes
+	line     int
+	section  string
+	defaults Progression
+	// line of each setting of the current section
+	seen map[string]int
+	// line of each row of the formation of the current level
+	rowLines []int
+	levels   []Level
+}
+
+func (p *levelParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", p.name, p.line, fmt.Sprintf(format, args...))
+}
+
+// Parses a level file, with the bunker shapes it can use.
+// Errors tell the name of the file and the line where the problem is.
+func ParseLevels(name string, data []byte, shapes Shapes) (LevelSet, error) {
+	p := &levelParser{name: name, shapes: shapes, seen: make(map[string]int)}
+	sectionLine := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		p.line++
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			if err := p.endSection(sectionLine); err != nil {
+				return LevelSet{}, err
+			}
+			if err := p.startSection(strings.TrimSpace(text[1 : len(text)-1])); err != nil {
+				return LevelSet{}, err
+			}
+			sectionLine = p.line
+			continue
+		}
+
+		key, value, found := strings.Cut(text, "=")
+		if !found {
+			return LevelSet{}, p.errorf("expected setting = value, got %q", text)
+		}
+		if err := p.setting(strings.TrimSpace(key), strings.Fields(value)); err != nil {
+			return LevelSet{}, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return LevelSet{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := p.endSection(sectionLine); err != nil {
+		return LevelSet{}, err
+	}
+	if len(p.levels) == 0 {
+		return LevelSet{}, fmt.Errorf("%s: there is no [level]", name)
+	}
+
+	// the shapes change the game as much as the level file itself
+	checksum := crc32.ChecksumIEEE(data)
+	for _, level := range p.levels {
+		checksum = crc32.Update(checksum, crc32.IEEETable, []byte(level.Layout.BunkerShape.String()))
+		for _, bunker := range level.Layout.Bunkers {
+			checksum = crc32.Update(checksum, crc32.IEEETable, []byte(bunker.Shape.String()))
+		}
+	}
+	return LevelSet{Levels: p.levels, Checksum: checksum}, nil
+}
+
+func (p *levelParser) startSection(section string) error {
+	switch section {
+	case "defaults":
+		if p.section != "" {
+			return p.errorf("[defaults] must come first")
+		}
+	case "level":
+		if p.section == "" {
+			return p.errorf("[defaults] must come before the first [level]")
+		}
+		shape, ok := p.shapes[defaultShape]
+		if !ok {
+			return p.errorf("there is no %s bunker shape", defaultShape)
+		}
+		p.levels = append(p.levels, Level{
+			Progression: p.defaults,
+			Layout: Layout{
+				Formation:   Formation{Left: 75, Spacing: 55},
+				BunkerCount: 4,
+				BunkerShape: shape,
+				BlockSize:   3,
+			},
+		})
+	default:
+		return p.errorf("unknown section [%s], expected [defaults] or [level]", section)
+	}
+	p.section = section
+	clear(p.seen)
+	p.rowLines = p.rowLines[:0]
+	return nil
+}
+
+// Checks that the section that just ended is complete
+func (p *levelParser) endSection(sectionLine int) error {
+	switch p.section {
+	case "defaults":
+		for _, key := range slices.Sorted(maps.Keys(progressionSettings)) {
+			if _, ok := p.seen[key]; !ok {
+				return fmt.Errorf("%s:%d: %s is missing from [defaults]", p.name, sectionLine, key)
+			}
+		}
+	case "level":
+		level := &p.levels[len(p.levels)-1]
+		if len(level.Layout.Formation.Rows) == 0 {
+			return fmt.Errorf("%s:%d: this level has no row of aliens", p.name, sectionLine)
+		}
+		// bunkers without their own shape or size take the ones of the level
+		for i := range level.Layout.Bunkers {
+			bunker := &level.Layout.Bunkers[i]
+			if bunker.Shape == nil {
+				bunker.Shape = level.Layout.BunkerShape
+			}
+			if bunker.BlockSize == 0 {
+				bunker.BlockSize = level.Layout.BlockSize
+			}
+		}
+		progression := level.Progression
+		if at, above := progression.MysteryMinInterval.above(progression.MysteryMaxInterval); above {
+			return fmt.Errorf("%s:%d: mystery_min is more than mystery_max at level %d", p.name, sectionLine, at)
+		}
+		return p.checkLayout(level, sectionLine)
+	}
+	return nil
+}
+
+// Checks that the fleet starts above the spaceships and that the fleet
+// and the bunkers fit in the world, from the left border to the right one
+func (p *levelParser) checkLayout(level *Level, sectionLine int) error {
+	const border = 25
+	formation := level.Layout.Formation
+	// the fleet starts lower and lower, down to the limit of start_y
+	startY := float32(max(level.Progression.StartY.Start, level.Progression.StartY.Limit))
+	spaceshipY := WorldHeight - 100 - spriteSize(atlas.SpaceshipSprite).Y
+	for row, types := range formation.Rows {
+		for col, alienType := range types {
+			if alienType == 0 {
+				continue
+			}
+			size := spriteSize(atlas.AlienSprite(alienType))
+			if formation.Left < border || formation.Left+float32(col)*formation.Spacing+size.X > WorldWidth-border {
+				return fmt.Errorf("%s:%d: this row does not fit between the borders of the %dx%d world, check left and spacing",
+					p.name, p.rowLines[row], WorldWidth, WorldHeight)
+			}
+			if startY+float32(row)*formation.Spacing+size.Y > spaceshipY {
+				return fmt.Errorf("%s:%d: this row starts below the spaceships, check start_y and spacing",
+					p.name, p.rowLines[row])
+			}
+		}
+	}
+
+	layout := level.Layout
+	for _, bunker := range layout.Bunkers {
+		width := float32(bunker.Shape.Width()) * bunker.BlockSize
+		height := float32(bunker.Shape.Height()) * bunker.BlockSize
+		if bunker.Position.X+width > WorldWidth || bunker.Position.Y+height > WorldHeight {
+			return fmt.Errorf("%s:%d: this bunker does not fit in the %dx%d world", p.name, bunker.line, WorldWidth, WorldHeight)
+		}
+	}
+	if len(layout.Bunkers) == 0 && float32(layout.BunkerCount*layout.BunkerShape.Width())*layout.BlockSize > WorldWidth {
+		return fmt.Errorf("%s:%d: %d bunkers do not fit side by side in the %dx%d world",
+			p.name, sectionLine, layout.BunkerCount, WorldWidth, WorldHeight)
+	}
+	return nil
+}
+
+func (p *levelParser) setting(key string, values []string) error {
+	if line, ok := p.seen[key]; ok && !repeatedSettings[key] {
+		return p.errorf("%s is already set on line %d", key, line)
+	}
+	p.seen[key] = p.line
+
+	if p.section == "" {
+		return p.errorf("%s must be in [defaults] or in a [level]", key)
+	}
+
+	if set, ok := progressionSettings[key]; ok {
+		numbers, err := p.numbers(key, values)
+		if err != nil {
+			return err
+		}
+		progression := &p.defaults
+		if p.section == "level" {
+			progression = &p.levels[len(p.levels)-1].Progression
+		}
+		if err := set(progression, numbers); err != nil {
+			return p.errorf("%s: %s", key, err)
+		}
+		return nil
+	}
+
+	if p.section != "level" {
+		if _, ok := levelSettings[key]; ok {
+			return p.errorf("%s can only be set in a [level]", key)
+		}
+		return p.errorf("unknown setting %s", key)
+	}
+	set, ok := levelSettings[key]
+	if !ok {
+		return p.errorf("unknown setting %s", key)
+	}
+	if err := set(p, &p.levels[len(p.levels)-1], values); err != nil {
+		return p.errorf("%s: %s", key, err)
+	}
+	return nil
+}
+
+func (p *levelParser) numbers(key string, values []string) ([]float64, error) {
+	numbers := make([]float64, len(values))
+	for i, value := range values {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || !finite(number) {
+			return nil, p.errorf("%s: %q is not a number", key, value)
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+// Settings of the layout of a single level
+var levelSettings = map[string]func(p *levelParser, level *Level, values []string) error{
+	"row": func(p *levelParser, level *Level, values []string) error {
+		if len(values) == 0 {
+			return errors.New("expected an alien type or . for each column")
+		}
+		row := make([]int32, len(values))
+		for i, value := range values {
+			if value == "." {
+				continue
+			}
+			alienType, err := strconv.Atoi(value)
+			if err != nil || alienType <= 0 {
+				return fmt.Errorf("expected an alien type or ., got %q", value)
+			}
+			if _, ok := sprites.Animations[atlas.AlienSprite(int32(alienType))]; !ok {
+				return fmt.Errorf("there is no alien of type %d", alienType)
+			}
+			row[i] = int32(alienType)
+		}
+		level.Layout.Formation.Rows = append(level.Layout.Formation.Rows, row)
+		p.rowLines = append(p.rowLines, p.line)
+		return nil
+	},
+	"left": func(p *levelParser, level *Level, values []string) error {
+		left, err := positive(values)
+		if err != nil {
+			return err
+		}
+		level.Layout.Formation.Left = left
+		return nil
+	},
+	"spacing": func(p *levelParser, level *Level, values []string) error {
+		spacing, err := positive(values)
+		if err != nil {
+			return err
+		}
+		level.Layout.Formation.Spacing = spacing
+		return nil
+	},
+	"bunkers": func(p *levelParser, level *Level, values []string) error {
+		if _, ok := p.seen["bunker"]; ok {
+			return errors.New("cannot be used together with bunker")
+		}
+		count := -1
+		if len(values) == 1 {
+			count, _ = strconv.Atoi(values[0])
+		}
+		if count < 0 || count > 10 {
+			return errors.New("expected a number of bunkers between 0 and 10")
+		}
+		level.Layout.BunkerCount = count
+		return nil
+	},
+	"bunker": func(p *levelParser, level *Level, values []string) error {
+		if _, ok := p.seen["bunkers"]; ok {
+			return errors.New("cannot be used together with bunkers")
+		}
+		if len(values) < 2 || len(values) > 4 {
+			return errors.New("expected X Y, then optionally the shape and the block size of the bunker")
+		}
+		x, errx := strconv.ParseFloat(values[0], 32)
+		y, erry := strconv.ParseFloat(values[1], 32)
+		if errx != nil || erry != nil || !finite(x) || !finite(y) || x < 0 || y < 0 {
+			return errors.New("expected the X and Y position of the bunker")
+		}
+		bunker := Bunker{Position: Vector2{X: float32(x), Y: float32(y)}, line: p.line}
+		if len(values) > 2 {
+			shape, err := p.shape(values[2])
+			if err != nil {
+				return err
+			}
+			bunker.Shape = shape
+		}
+		if len(values) > 3 {
+			size, err := blockSize(values[3:])
+			if err != nil {
+				return err
+			}
+			bunker.BlockSize = size
+		}
+		level.Layout.Bunkers = append(level.Layout.Bunkers, bunker)
+		return nil
+	},
+	"bunker_shape": func(p *levelParser, level *Level, values []string) error {
+		if len(values) != 1 {
+			return errors.New("expected the name of a shape")
+		}
+		shape, err := p.shape(values[0])
+		if err != nil {
+			return err
+		}
+		level.Layout.BunkerShape = shape
+		return nil
+	},
+	"block_size": func(p *levelParser, level *Level, values []string) error {
+		size, err := blockSize(values)
+		if err != nil {
+			return err
+		}
+		level.Layout.BlockSize = size
+		return nil
+	},
+}
+
+func (p *levelParser) shape(name string) (Shape, error) {
+	shape, ok := p.shapes[name]
+	if !ok {
+		return nil, fmt.Errorf("there is no bunker shape called %s", name)
+	}
+	return shape, nil
+}
+
+func blockSize(values []string) (float32, error) {
+	size, err := positive(values)
+	if err != nil || size > 20 {
+		return 0, errors.New("expected a block size between 1 and 20 pixels")
+	}
+	return size, nil
+}
+
+func positive(values []string) (float32, error) {
+	if len(values) == 1 {
+		if value, err := strconv.ParseFloat(values[0], 32); err == nil && finite(value) && value > 0 {
+			return float32(value), nil
+		}
+	}
+	return 0, errors.New("expected a number more than 0")
+}
+
+// ParseFloat also reads "nan" and "inf", which no setting can take
+func finite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
@@ -0,0 +1,198 @@
+package game
+
+import (
+	"strings"
+	"testing"
+)
+
+// The levels the tests break, one line at a time
+const testLevels = `[defaults]
+start_y         = 110 +15 230
+alien_speed     = 60
+speed_up        = 1 8 3
+drop            = 20
+fire_interval   = 0.35
+laser_speed     = 360
+mystery_min     = 10 -0.5 6
+mystery_max     = 20 -1 12
+respawn_delay   = 2
+invulnerability = 2
+blink_interval  = 0.1
+
+[level]
+row = 3 3 3
+row = 1 1 1
+`
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		name string
+		// replaces the first old line of the test levels with new
+		old, new string
+		want     string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "unknown section",
+			old:  "[level]", new: "[levels]",
+			want: "levels.txt:14: unknown section [levels], expected [defaults] or [level]",
+		},
+		{
+			name: "missing setting",
+			old:  "drop            = 20\n", new: "",
+			want: "levels.txt:1: drop is missing from [defaults]",
+		},
+		{
+			name: "setting set twice",
+			old:  "row = 3 3 3", new: "left = 50\nleft = 60\nrow = 3 3 3",
+			want: "levels.txt:16: left is already set on line 15",
+		},
+		{
+			name: "not a setting",
+			old:  "row = 1 1 1", new: "row 1 1 1",
+			want: `levels.txt:16: expected setting = value, got "row 1 1 1"`,
+		},
+		{
+			name: "not a number",
+			old:  "drop            = 20", new: "drop = twenty",
+			want: `levels.txt:5: drop: "twenty" is not a number`,
+		},
+		{
+			name: "not a finite number",
+			old:  "fire_interval   = 0.35", new: "fire_interval = nan 1 5",
+			want: `levels.txt:6: fire_interval: "nan" is not a number`,
+		},
+		{
+			name: "infinite spacing",
+			old:  "row = 3 3 3", new: "spacing = inf\nrow = 3 3 3",
+			want: "levels.txt:15: spacing: expected a number more than 0",
+		},
+		{
+			name: "bunker at no position",
+			old:  "row = 1 1 1", new: "row = 1 1 1\nbunker = NaN 600",
+			want: "levels.txt:17: bunker: expected the X and Y position of the bunker",
+		},
+		{
+			name: "step of 0 that never reaches the limit",
+			old:  "alien_speed     = 60", new: "alien_speed = 60 0 120",
+			want: "levels.txt:3: alien_speed: with a step of 0 the limit must be the start",
+		},
+		{
+			name: "step away from the limit",
+			old:  "alien_speed     = 60", new: "alien_speed = 60 -1 120",
+			want: "levels.txt:3: alien_speed: the limit is never reached with this step",
+		},
+		{
+			name: "mystery_min above mystery_max from the first level",
+			old:  "mystery_min     = 10 -0.5 6", new: "mystery_min = 30",
+			want: "levels.txt:14: mystery_min is more than mystery_max at level 1",
+		},
+		{
+			name: "mystery_min above mystery_max at a later level",
+			old:  "mystery_min     = 10 -0.5 6", new: "mystery_min = 5 +1 20",
+			want: "levels.txt:14: mystery_min is more than mystery_max at level 9",
+		},
+		{
+			name: "unknown alien",
+			old:  "row = 3 3 3", new: "row = 3 9 3",
+			want: "levels.txt:15: row: there is no alien of type 9",
+		},
+		{
+			name: "level without aliens",
+			old:  "row = 3 3 3\nrow = 1 1 1", new: "left = 100",
+			want: "levels.txt:14: this level has no row of aliens",
+		},
+		{
+			name: "no level",
+			old:  "[level]\nrow = 3 3 3\nrow = 1 1 1", new: "",
+			want: "levels.txt: there is no [level]",
+		},
+		{
+			name: "row wider than the world",
+			old:  "row = 1 1 1", new: "row = 1 1 1 1 1 1 1 1 1 1 1 1 1 1",
+			want: "levels.txt:16: this row does not fit between the borders of the 800x800 world, check left and spacing",
+		},
+		{
+			name: "row starting below the spaceships",
+			old:  "start_y         = 110 +15 230", new: "start_y = 110 +15 600",
+			want: "levels.txt:16: this row starts below the spaceships, check start_y and spacing",
+		},
+		{
+			name: "bunker out of the world",
+			old:  "row = 1 1 1", new: "row = 1 1 1\nbunker = 100 600\nbunker = 780 600",
+			want: "levels.txt:18: this bunker does not fit in the 800x800 world",
+		},
+		{
+			name: "too many bunkers",
+			old:  "row = 1 1 1", new: "row = 1 1 1\nbunkers = 10\nblock_size = 20",
+			want: "levels.txt:14: 10 bunkers do not fit side by side in the 800x800 world",
+		},
+		{
+			name: "bunkers with bunker",
+			old:  "row = 1 1 1", new: "row = 1 1 1\nbunkers = 2\nbunker = 100 600",
+			want: "levels.txt:18: bunker: cannot be used together with bunkers",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testLevels
+			if tt.old != "" {
+				if !strings.Contains(data, tt.old) {
+					t.Fatalf("%q is not in the test levels", tt.old)
+				}
+				data = strings.Replace(data, tt.old, tt.new, 1)
+			}
+			_, err := ParseLevels("levels.txt", []byte(data), DefaultShapes)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("got error %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurve(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve Curve
+		level int32
+		want  float64
+	}{
+		{name: "first level", curve: Curve{Start: 10, Step: 2, Limit: 20}, level: 1, want: 10},
+		{name: "going up", curve: Curve{Start: 10, Step: 2, Limit: 20}, level: 3, want: 14},
+		{name: "up to the limit", curve: Curve{Start: 10, Step: 2, Limit: 20}, level: 100, want: 20},
+		{name: "down to the limit", curve: Curve{Start: 10, Step: -0.5, Limit: 6}, level: 100, want: 6},
+		{name: "step of 0", curve: Curve{Start: 10, Step: 0, Limit: 20}, level: 5, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.At(tt.level); got != tt.want {
+				t.Errorf("got %g at level %d, want %g", got, tt.level, tt.want)
+			}
+		})
+	}
+}
+
+// Levels built by hand, not parsed, may still cross the intervals
+func TestMysteryIntervals(t *testing.T) {
+	progression := DefaultLevels.Levels[0].Progression
+	progression.MysteryMinInterval = Curve{Start: 5, Step: 1, Limit: 30}
+	progression.MysteryMaxInterval = Curve{Start: 20, Step: -1, Limit: 10}
+	for level := int32(1); level <= 30; level++ {
+		params := progression.Level(level)
+		if params.MysteryMinInterval > params.MysteryMaxInterval {
+			t.Errorf("level %d: got mystery interval %d to %d", level, params.MysteryMinInterval, params.MysteryMaxInterval)
+		}
+	}
+
+	g := New(WorldWidth, WorldHeight, 1)
+	g.params.MysteryMinInterval, g.params.MysteryMaxInterval = 20, 10
+	if got := g.mysterySpawnInterval(); got != 20 {
+		t.Errorf("got a spawn interval of %g, want the minimum 20", got)
+	}
+}
@@ -1,34 +1,59 @@
 package game
 
-import (
-	"goinvaders/internal/assets"
+import "goinvaders/internal/assets/atlas"
 
-	rl "github.com/gen2brain/raylib-go/raylib"
-)
+// Speed of the mystery ship in pixels per second
+const mysteryShipSpeed float32 = 180
+
+// Frames per second of the mystery ship animation
+const mysteryFPS = 6
 
 type MysteryShip struct {
-	image    rl.Texture2D
-	position rl.Vector2
-	speed    int32
+	position Vector2
+	previous Vector2
+	size     Vector2
+	speed    float32
 	alive    bool
+	// seconds since the mystery ship appeared
+	age float64
 }
 
-func NewMysteryShip() MysteryShip {
-	return MysteryShip{
-		image: assets.GetMysteryImage(),
+// The mystery ship comes in from a side of the world
+// and flies to the other side
+func NewMysteryShip(fromLeft bool, worldWidth float32) *MysteryShip {
+	m := &MysteryShip{
+		size: spriteSize(atlas.MysterySprite),
 	}
+	m.Spawn(fromLeft, worldWidth)
+	return m
+}
+
+func (m *MysteryShip) InterpolatedPosition(alpha float32) Vector2 {
+	return Lerp(m.previous, m.position, alpha)
+}
+
+func (m *MysteryShip) savePosition() {
+	m.previous = m.position
+}
+
+func (m *MysteryShip) Alive() bool {
+	return m.alive
+}
+
+func (m *MysteryShip) Layer() Layer {
+	return AlienLayer
 }
 
-func (m *MysteryShip) GetRect() rl.Rectangle {
+func (m *MysteryShip) GetRect() Rectangle {
 	if m.alive {
-		return rl.Rectangle{
+		return Rectangle{
 			X:      m.position.X,
 			Y:      m.position.Y,
-			Width:  float32(m.image.Width),
-			Height: float32(m.image.Height),
+			Width:  m.size.X,
+			Height: m.size.Y,
 		}
 	} else {
-		return rl.Rectangle{
+		return Rectangle{
 			X:      m.position.X,
 			Y:      m.position.Y,
 			Width:  0,
@@ -37,30 +62,30 @@ func (m *MysteryShip) GetRect() rl.Rectangle {
 	}
 }
 
-func (m *MysteryShip) Spawn() {
+func (m *MysteryShip) Spawn(fromLeft bool, worldWidth float32) {
 	m.position.Y = 90
-	side := rl.GetRandomValue(0, 1)
-	if side == 0 {
+	if fromLeft {
 		m.position.X = 25
-		m.speed = 3
+		m.speed = mysteryShipSpeed
 	} else {
-		m.position.X = float32(rl.GetScreenWidth()) - float32(m.image.Width) - 25
-		m.speed = -3
+		m.position.X = worldWidth - m.size.X - 25
+		m.speed = -mysteryShipSpeed
 	}
+	m.previous = m.position
 	m.alive = true
+	m.age = 0
 }
 
-func (m *MysteryShip) Update() {
-	if m.alive {
-		m.position.X += float32(m.speed)
-		if m.position.X > float32(rl.GetScreenWidth()-int(m.image.Width))-25 || m.position.X < 25 {
+func (m *MysteryShip) Update(ctx *Context) {
+	m.age += TickDuration
+	if m.alive && !ctx.Frozen {
+		m.position.X += perTick(m.speed)
+		if m.position.X > ctx.Game.width-m.size.X-25 || m.position.X < 25 {
 			m.alive = false
 		}
 	}
 }
 
-func (m *MysteryShip) Draw() {
-	if m.alive {
-		rl.DrawTextureV(m.image, m.position, rl.White)
-	}
+func (m *MysteryShip) Draw(r Renderer) {
+	r.DrawSprite(atlas.MysterySprite, int(m.age*mysteryFPS), m.InterpolatedPosition(r.Alpha()), white)
 }
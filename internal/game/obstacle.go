@@ -1,48 +1,43 @@
 package game
 
-import (
-	rl "github.com/gen2brain/raylib-go/raylib"
-)
-
-var (
-	grid = [][]uint8{
-		{0, 0, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0},
-		{0, 0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0},
-		{0, 0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0},
-		{0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1},
-		{1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1},
-	}
-)
+import "slices"
 
+// Obstacle is a bunker made of square blocks. The blocks are also kept
+// in a grid by row and column, so that finding the blocks under a
+// rectangle only looks at the few cells it covers.
 type Obstacle struct {
-	position rl.Vector2
-	blocks   []*Block
-}
-
-func GetObstacleWidth() int {
-	return len(grid[0]) * 3
+	position  Vector2
+	blockSize float32
+	blocks    []*Block
+	// the blocks by row and column, nil where there is none (left)
+	cells  [][]*Block
+	bounds Rectangle
 }
 
-func NewObstacle(posx, posy float32) *Obstacle {
+// Builds a bunker of the given shape, with blocks of blockSize pixels
+func NewObstacle(posx, posy float32, shape Shape, blockSize float32) *Obstacle {
 	obstacle := &Obstacle{
-		position: rl.Vector2{X: posx, Y: posy},
-		blocks:   make([]*Block, 0),
+		position:  Vector2{X: posx, Y: posy},
+		blockSize: blockSize,
+		blocks:    make([]*Block, 0),
+		cells:     make([][]*Block, len(shape)),
+		bounds: Rectangle{
+			X:      posx,
+			Y:      posy,
+			Width:  float32(shape.Width()) * blockSize,
+			Height: float32(len(shape)) * blockSize,
+		},
 	}
 
-	for row := range len(grid) {
-		for col := range len(grid[0]) {
-			if grid[row][col] == 1 {
-				blockx := posx + float32(col*3)
-				blocky := posy + float32(row*3)
-				obstacle.blocks = append(obstacle.blocks, NewBlock(blockx, blocky))
+	for row := range shape {
+		obstacle.cells[row] = make([]*Block, len(shape[row]))
+		for col, filled := range shape[row] {
+			if filled {
+				blockx := posx + float32(col)*blockSize
+				blocky := posy + float32(row)*blockSize
+				block := NewBlock(blockx, blocky, blockSize)
+				obstacle.blocks = append(obstacle.blocks, block)
+				obstacle.cells[row][col] = block
 			}
 		}
 	}
@@ -50,8 +45,56 @@ func NewObstacle(posx, posy float32) *Obstacle {
 	return obstacle
 }
 
-func (o *Obstacle) Draw() {
+// The rectangle around the whole bunker, as it was built
+func (o *Obstacle) GetRect() Rectangle {
+	return o.bounds
+}
+
+// A bunker is gone with its last block
+func (o *Obstacle) Alive() bool {
+	return len(o.blocks) > 0
+}
+
+func (o *Obstacle) Layer() Layer {
+	return BunkerLayer
+}
+
+// Bunkers only change when they are hit
+func (o *Obstacle) Update(ctx *Context) {}
+
+func (o *Obstacle) Draw(r Renderer) {
 	for _, block := range o.blocks {
-		block.Draw()
+		r.DrawRectangle(block.GetRect(), yellow)
+	}
+}
+
+// Destroys the blocks overlapping rect, and tells if there were any
+func (o *Obstacle) Hit(rect Rectangle) bool {
+	if !CheckCollisionRecs(rect, o.bounds) {
+		return false
+	}
+
+	firstRow := max(int((rect.Y-o.position.Y)/o.blockSize), 0)
+	lastRow := min(int((rect.Y+rect.Height-o.position.Y)/o.blockSize), len(o.cells)-1)
+	hit := false
+	for row := firstRow; row <= lastRow; row++ {
+		cells := o.cells[row]
+		firstCol := max(int((rect.X-o.position.X)/o.blockSize), 0)
+		lastCol := min(int((rect.X+rect.Width-o.position.X)/o.blockSize), len(cells)-1)
+		for col := firstCol; col <= lastCol; col++ {
+			block := cells[col]
+			if block != nil && CheckCollisionRecs(rect, block.GetRect()) {
+				block.active = false
+				cells[col] = nil
+				hit = true
+			}
+		}
+	}
+
+	if hit {
+		o.blocks = slices.DeleteFunc(o.blocks, func(block *Block) bool {
+			return !block.active
+		})
 	}
+	return hit
 }
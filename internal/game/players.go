@@ -0,0 +1,218 @@
+package game
+
+import "slices"
+
+// Mode tells how many players there are and how they share the game
+type Mode uint8
+
+const (
+	OnePlayer Mode = iota
+	// Two players taking turns, the turn changes when a life is lost
+	TwoPlayers
+	// Two spaceships on screen at the same time, each player with their own lives
+	CoOp
+	// Two spaceships on screen at the same time, sharing the lives of both players
+	SharedCoOp
+)
+
+var modeNames = map[Mode]string{
+	OnePlayer:  "1P",
+	TwoPlayers: "2P",
+	CoOp:       "co-op",
+	SharedCoOp: "co-op shared",
+}
+
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func (m Mode) Players() int {
+	if m == OnePlayer {
+		return 1
+	}
+	return 2
+}
+
+// Tells if the players play at the same time, each with a spaceship
+func (m Mode) Simultaneous() bool {
+	return m == CoOp || m == SharedCoOp
+}
+
+// Number of spaceships on screen
+func (m Mode) Ships() int {
+	if m.Simultaneous() {
+		return m.Players()
+	}
+	return 1
+}
+
+// Seconds the world stands still when a player's turn starts,
+// while the presentation layer tells whose turn it is
+const TurnDelay = 2.0
+
+// Player holds everything that belongs to a player. In alternating games
+// the current player plays in the fields of the game, while the others
+// wait here for their turn with their fleet and bunkers as they left them.
+type Player struct {
+	score           int32
+	lives           int32
+	level           int32
+	params          LevelParams
+	aliens          []*Alien
+	obstacles       []*Obstacle
+	aliensDirection int32
+	fleetSize       int
+	fleetStep       int
+	fleetTravel     float32
+}
+
+func (p *Player) Score() int32 {
+	return p.score
+}
+
+func (p *Player) Lives() int32 {
+	return p.lives
+}
+
+func (p *Player) Level() int32 {
+	return p.level
+}
+
+// Changes the mode, used from the next game on
+func (g *Game) SetMode(mode Mode) {
+	g.nextMode = mode
+}
+
+func (g *Game) Mode() Mode {
+	return g.mode
+}
+
+// Index of the player whose turn it is, always 0 in co-op games
+func (g *Game) CurrentPlayer() int {
+	return g.current
+}
+
+// Returns the state of every player, with the level and the lives
+// of the players in the game brought up to date
+func (g *Game) Players() []Player {
+	for i := range g.players {
+		if g.mode.Simultaneous() || i == g.current {
+			g.players[i].level = g.level
+		}
+		g.players[i].lives = *g.livesOf(i)
+	}
+	return g.players
+}
+
+// Tells if the turn of the current player is about to start
+func (g *Game) TurnStarting() bool {
+	return g.time < g.turnUntil
+}
+
+// The player flying the spaceship
+func (g *Game) shipPlayer(ship *Spaceship) int {
+	if g.mode.Simultaneous() {
+		return ship.player
+	}
+	return g.current
+}
+
+// The lives of the player, which are those of the first player
+// when the lives are shared
+func (g *Game) livesOf(player int) *int32 {
+	if g.mode == SharedCoOp {
+		return &g.players[0].lives
+	}
+	return &g.players[player].lives
+}
+
+// Sets up the spaceships and the players. In alternating games every player
+// gets a fresh first level, the first one playing first.
+func (g *Game) initPlayers() {
+	g.ships = make([]*Spaceship, g.mode.Ships())
+	for i := range g.ships {
+		g.ships[i] = NewSpaceship(g.width, g.height, i)
+	}
+
+	g.players = make([]Player, g.mode.Players())
+	for i := range g.players {
+		g.players[i].lives = g.difficulty.Lives()
+	}
+	if g.mode == SharedCoOp {
+		// the pool holds the lives of both players
+		g.players[0].lives *= int32(len(g.players))
+	}
+
+	g.current = 0
+	g.turnUntil = 0
+	if g.mode != TwoPlayers {
+		g.level = 0
+		g.ResetGame()
+		g.InitLevel()
+		return
+	}
+	for g.current = len(g.players) - 1; g.current >= 0; g.current-- {
+		g.level = 0
+		g.ResetGame()
+		g.InitLevel()
+		g.saveTurn()
+	}
+	g.current = 0
+	g.turnUntil = g.time + TurnDelay
+}
+
+func (g *Game) saveTurn() {
+	p := &g.players[g.current]
+	p.level = g.level
+	p.params = g.params
+	p.aliens = slices.Collect(All[*Alien](g.world))
+	p.obstacles = slices.Collect(All[*Obstacle](g.world))
+	p.aliensDirection = g.aliensDirection
+	p.fleetSize = g.fleetSize
+	p.fleetStep = g.fleetStep
+	p.fleetTravel = g.fleetTravel
+}
+
+// Hands the game over to another player, where they left it
+func (g *Game) switchTurn(next int) {
+	g.saveTurn()
+	g.current = next
+	p := g.players[next]
+	g.level = p.level
+	g.params = p.params
+	g.aliensDirection = p.aliensDirection
+	g.fleetSize = p.fleetSize
+	g.fleetStep = p.fleetStep
+	g.fleetTravel = p.fleetTravel
+
+	// the lasers and the mystery ship of the other player are gone
+	g.resetShip(g.ships[0])
+	g.world.Clear()
+	for _, ship := range g.ships {
+		g.world.Add(ship)
+	}
+	for _, obstacle := range p.obstacles {
+		g.world.Add(obstacle)
+	}
+	for _, alien := range p.aliens {
+		g.world.Add(alien)
+	}
+	g.msTimeLastSpawned = g.time
+	g.timeLastAlienFired = g.time
+	g.turnUntil = g.time + TurnDelay
+}
+
+// The player who plays after the current one loses a life:
+// the next player with lives left, or the current one if there is none
+func (g *Game) nextPlayer() int {
+	for i := 1; i < len(g.players); i++ {
+		next := (g.current + i) % len(g.players)
+		if g.players[next].lives > 0 {
+			return next
+		}
+	}
+	return g.current
+}
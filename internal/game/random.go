@@ -0,0 +1,27 @@
+package game
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// All the gameplay randomness comes from a source owned by the game.
+// Given the same seed and the same inputs, a game always plays the same way.
+
+// Returns a seed taken from the clock, used when the player does not provide one
+func NewSeed() uint64 {
+	return uint64(time.Now().UnixNano())
+}
+
+func newRandom(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// Returns a random value between min and max (both included), like rl.GetRandomValue.
+// It is min when max is less than min.
+func (g *Game) randomValue(min, max int32) int32 {
+	if max < min {
+		return min
+	}
+	return min + g.rand.Int32N(max-min+1)
+}
@@ -0,0 +1,188 @@
+package game
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RulesetVersion identifies the gameplay rules.
+// It must be increased whenever a change makes old replays play differently.
+const RulesetVersion uint16 = 11
+
+var replayMagic = [4]byte{'G', 'I', 'R', 'P'}
+
+// A Replay holds everything needed to play a game again:
+// the world size, the seed, the levels, the difficulty, the mode and the
+// player input of every tick.
+type Replay struct {
+	Ruleset uint16
+	Width   int32
+	Height  int32
+	Seed    uint64
+	// Checksum of the level file the game was played with
+	Levels     uint32
+	Difficulty Difficulty
+	Mode       Mode
+	Inputs     []TickInput
+}
+
+// Creates an empty replay for the game that is about to start
+func NewReplay(g *Game) *Replay {
+	return &Replay{
+		Ruleset:    RulesetVersion,
+		Width:      int32(g.width),
+		Height:     int32(g.height),
+		Seed:       g.seed,
+		Levels:     g.levels.Checksum,
+		Difficulty: g.difficulty,
+		Mode:       g.mode,
+		Inputs:     make([]TickInput, 0),
+	}
+}
+
+func (r *Replay) Record(inputs TickInput) {
+	r.Inputs = append(r.Inputs, inputs)
+}
+
+// Checks that the replay was recorded with these levels
+func (r *Replay) CheckLevels(levels LevelSet) error {
+	if r.Levels != levels.Checksum {
+		return errors.New("replay was recorded with a different level file")
+	}
+	return nil
+}
+
+// Creates the game the replay was recorded from
+func (r *Replay) NewGame(levels LevelSet) *Game {
+	game := New(r.Width, r.Height, r.Seed)
+	game.SetDifficulty(r.Difficulty)
+	game.SetMode(r.Mode)
+	game.SetLevels(levels)
+	game.Start()
+	return game
+}
+
+func (i Input) bits() byte {
+	var b byte
+	if i.Left {
+		b |= 1
+	}
+	if i.Right {
+		b |= 2
+	}
+	if i.Fire {
+		b |= 4
+	}
+	return b
+}
+
+func inputFromBits(b byte) Input {
+	return Input{
+		Left:  b&1 != 0,
+		Right: b&2 != 0,
+		Fire:  b&4 != 0,
+	}
+}
+
+// Each spaceship takes 3 bits, so that a tick still fits in a byte
+func (t TickInput) bits() byte {
+	var b byte
+	for i, input := range t {
+		b |= input.bits() << (3 * i)
+	}
+	return b
+}
+
+func tickInputFromBits(b byte) TickInput {
+	var t TickInput
+	for i := range t {
+		t[i] = inputFromBits(b >> (3 * i))
+	}
+	return t
+}
+
+// Writes the replay in a compact binary format.
+// The inputs are run-length encoded, since keys are held for many ticks.
+func (r *Replay) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.Write(replayMagic[:])
+	binary.Write(bw, binary.LittleEndian, r.Ruleset)
+	binary.Write(bw, binary.LittleEndian, r.Width)
+	binary.Write(bw, binary.LittleEndian, r.Height)
+	binary.Write(bw, binary.LittleEndian, r.Seed)
+	binary.Write(bw, binary.LittleEndian, r.Levels)
+	binary.Write(bw, binary.LittleEndian, r.Difficulty)
+	binary.Write(bw, binary.LittleEndian, r.Mode)
+	bw.Write(binary.AppendUvarint(nil, uint64(len(r.Inputs))))
+
+	for start := 0; start < len(r.Inputs); {
+		end := start + 1
+		for end < len(r.Inputs) && r.Inputs[end] == r.Inputs[start] {
+			end++
+		}
+		bw.Write(binary.AppendUvarint(nil, uint64(end-start)))
+		bw.WriteByte(r.Inputs[start].bits())
+		start = end
+	}
+	return bw.Flush()
+}
+
+// Longest replay that can be read, a whole day of play
+const maxReplayTicks = 24 * 60 * 60 * TicksPerSecond
+
+func ReadReplay(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil || magic != replayMagic {
+		return nil, errors.New("not a replay file")
+	}
+
+	replay := &Replay{}
+	for _, field := range []any{&replay.Ruleset, &replay.Width, &replay.Height, &replay.Seed, &replay.Levels, &replay.Difficulty, &replay.Mode} {
+		if err := binary.Read(br, binary.LittleEndian, field); err != nil {
+			return nil, fmt.Errorf("could not read the replay header: %w", err)
+		}
+	}
+	if replay.Ruleset != RulesetVersion {
+		return nil, fmt.Errorf("replay was recorded with ruleset %d, this game uses ruleset %d", replay.Ruleset, RulesetVersion)
+	}
+	if _, ok := difficultyNames[replay.Difficulty]; !ok {
+		return nil, fmt.Errorf("replay has an unknown difficulty %d", replay.Difficulty)
+	}
+	if _, ok := modeNames[replay.Mode]; !ok {
+		return nil, fmt.Errorf("replay has an unknown mode %d", replay.Mode)
+	}
+
+	ticks, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the replay length: %w", err)
+	}
+	// the length comes from the file: a forged one must not make us
+	// allocate more than the longest game that can be recorded
+	if ticks > maxReplayTicks {
+		return nil, fmt.Errorf("replay is too long: %d ticks", ticks)
+	}
+	replay.Inputs = make([]TickInput, 0)
+	for uint64(len(replay.Inputs)) < ticks {
+		count, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("replay truncated at tick %d: %w", len(replay.Inputs), err)
+		}
+		bits, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("replay truncated at tick %d: %w", len(replay.Inputs), err)
+		}
+		if count == 0 || uint64(len(replay.Inputs))+count > ticks {
+			return nil, fmt.Errorf("replay corrupted at tick %d", len(replay.Inputs))
+		}
+		input := tickInputFromBits(bits)
+		for range count {
+			replay.Inputs = append(replay.Inputs, input)
+		}
+	}
+	return replay, nil
+}
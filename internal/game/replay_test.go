@@ -0,0 +1,137 @@
+package game
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Plays the inputs, going on to the next level after each level up,
+// and returns every event published
+func replay(g *Game, inputs []TickInput) []Event {
+	events := make([]Event, 0)
+	g.Events().SubscribeAll(func(event Event) {
+		events = append(events, event)
+	})
+	for _, input := range inputs {
+		if g.State() == LevelUp {
+			g.NextLevel()
+		}
+		g.Update(input[:]...)
+	}
+	return events
+}
+
+// Inputs that change every third of a second, the same for a given seed
+func scriptedInputs(seed uint64, ticks int) []TickInput {
+	r := rand.New(rand.NewPCG(seed, seed))
+	inputs := make([]TickInput, ticks)
+	var input TickInput
+	for tick := range inputs {
+		if tick%20 == 0 {
+			for ship := range input {
+				input[ship] = Input{Left: r.IntN(3) == 0, Right: r.IntN(3) == 0, Fire: r.IntN(2) == 0}
+			}
+		}
+		inputs[tick] = input
+	}
+	return inputs
+}
+
+func TestReplayDeterminism(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       Mode
+		difficulty Difficulty
+		seed       uint64
+	}{
+		{name: "one player", mode: OnePlayer, difficulty: Normal, seed: 42},
+		{name: "two players", mode: TwoPlayers, difficulty: Easy, seed: 7},
+		{name: "co-op", mode: CoOp, difficulty: Hard, seed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(WorldWidth, WorldHeight, tt.seed)
+			g.SetMode(tt.mode)
+			g.SetDifficulty(tt.difficulty)
+			g.Reset(tt.seed)
+			recorded := NewReplay(g)
+			g.Start()
+			recorded.Inputs = scriptedInputs(tt.seed, 60*TicksPerSecond)
+			events := replay(g, recorded.Inputs)
+
+			var buf bytes.Buffer
+			if err := recorded.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			read, err := ReadReplay(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(read, recorded) {
+				t.Fatalf("read %+v, want %+v", read, recorded)
+			}
+			if err := read.CheckLevels(DefaultLevels); err != nil {
+				t.Fatal(err)
+			}
+
+			played := read.NewGame(DefaultLevels)
+			playedEvents := replay(played, read.Inputs)
+			if !reflect.DeepEqual(playedEvents, events) {
+				t.Errorf("the replay published %d events, the game %d, and they differ", len(playedEvents), len(events))
+			}
+			if played.Score() != g.Score() || played.Level() != g.Level() || played.State() != g.State() {
+				t.Errorf("replay ended with score %d level %d %v, the game with score %d level %d %v",
+					played.Score(), played.Level(), played.State(), g.Score(), g.Level(), g.State())
+			}
+		})
+	}
+}
+
+func TestReadReplayErrors(t *testing.T) {
+	g := New(WorldWidth, WorldHeight, 1)
+	g.Reset(1)
+	recorded := NewReplay(g)
+	recorded.Inputs = scriptedInputs(1, 100)
+	var buf bytes.Buffer
+	if err := recorded.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+	// the header is followed by the number of ticks
+	header := valid[:28]
+
+	withTicks := func(ticks uint64, runs ...byte) []byte {
+		data := binary.AppendUvarint(bytes.Clone(header), ticks)
+		return append(data, runs...)
+	}
+	otherRuleset := bytes.Clone(valid)
+	binary.LittleEndian.PutUint16(otherRuleset[4:], RulesetVersion-1)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "empty", data: nil, want: "not a replay file"},
+		{name: "not a replay", data: []byte("GIF89a..."), want: "not a replay file"},
+		{name: "short header", data: valid[:10], want: "could not read the replay header"},
+		{name: "other ruleset", data: otherRuleset, want: "ruleset"},
+		{name: "too long", data: withTicks(maxReplayTicks + 1), want: "too long"},
+		{name: "huge length", data: withTicks(1 << 62), want: "too long"},
+		{name: "truncated", data: valid[:len(valid)-1], want: "truncated"},
+		{name: "run past the end", data: withTicks(2, 3, 0), want: "corrupted at tick 0"},
+		{name: "empty run", data: withTicks(2, 0, 0), want: "corrupted at tick 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadReplay(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want one with %q", err, tt.want)
+			}
+		})
+	}
+}
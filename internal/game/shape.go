@@ -0,0 +1,160 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"goinvaders/internal/assets/bunkers"
+	"image/png"
+	"path/filepath"
+	"strings"
+)
+
+// Largest shape allowed, in blocks
+const maxShapeSize = 100
+
+// Shape is the outline of a bunker, row by row from the top:
+// true where there is a block
+type Shape [][]bool
+
+// Shapes by name, the name being the file name without extension
+type Shapes map[string]Shape
+
+// The shapes built into the game
+var DefaultShapes = mustParseShapes(map[string][]byte{
+	"arch.txt":    bunkers.Arch_txt,
+	"classic.txt": bunkers.Classic_txt,
+	"dome.png":    bunkers.Dome_png,
+})
+
+func mustParseShapes(files map[string][]byte) Shapes {
+	shapes := make(Shapes)
+	for file, data := range files {
+		shape, err := ParseShape(file, data)
+		if err != nil {
+			panic(err)
+		}
+		shapes[ShapeName(file)] = shape
+	}
+	return shapes
+}
+
+// Tells if the file is a shape file, a text file or a png image
+func IsShapeFile(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".txt" || ext == ".png"
+}
+
+func ShapeName(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+func (s Shape) Width() int {
+	width := 0
+	for _, row := range s {
+		width = max(width, len(row))
+	}
+	return width
+}
+
+func (s Shape) Height() int {
+	return len(s)
+}
+
+// Draws the shape the way it is written in text files
+func (s Shape) String() string {
+	var sb strings.Builder
+	for _, row := range s {
+		for _, block := range row {
+			if block {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Parses a shape file, either text or png depending on its name
+func ParseShape(file string, data []byte) (Shape, error) {
+	var shape Shape
+	var err error
+	if strings.ToLower(filepath.Ext(file)) == ".png" {
+		shape, err = ParseShapeImage(file, data)
+	} else {
+		shape, err = ParseShapeText(file, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case shape.Height() == 0:
+		return nil, fmt.Errorf("%s: the shape has no blocks", file)
+	case shape.Width() > maxShapeSize || shape.Height() > maxShapeSize:
+		return nil, fmt.Errorf("%s: the shape is larger than %d by %d blocks", file, maxShapeSize, maxShapeSize)
+	}
+	return shape, nil
+}
+
+// Parses a shape drawn with # for the blocks, and . or spaces for the holes.
+// Empty lines before and after the drawing are ignored.
+func ParseShapeText(file string, data []byte) (Shape, error) {
+	var shape Shape
+	line := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), " \r")
+		if text == "" && len(shape) == 0 {
+			continue
+		}
+		row := make([]bool, len(text))
+		for i, c := range text {
+			switch c {
+			case '#':
+				row[i] = true
+			case '.', ' ':
+			default:
+				return nil, fmt.Errorf("%s:%d: unexpected %q, use # for the blocks and . for the holes", file, line, c)
+			}
+		}
+		shape = append(shape, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	for len(shape) > 0 && len(shape[len(shape)-1]) == 0 {
+		shape = shape[:len(shape)-1]
+	}
+	return shape, nil
+}
+
+// Reads a shape from a black and white image, one pixel per block.
+// Dark pixels are blocks, while light or transparent pixels are holes.
+func ParseShapeImage(file string, data []byte) (Shape, error) {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() > maxShapeSize || bounds.Dy() > maxShapeSize {
+		return nil, fmt.Errorf("%s: the image is larger than %d by %d pixels", file, maxShapeSize, maxShapeSize)
+	}
+
+	shape := make(Shape, bounds.Dy())
+	for y := range shape {
+		shape[y] = make([]bool, bounds.Dx())
+		for x := range shape[y] {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			// colors are premultiplied, so transparent pixels are light enough
+			opaque := a >= 0x8000
+			dark := (r+g+b)/3 < a/2
+			shape[y][x] = opaque && dark
+		}
+	}
+	return shape, nil
+}
@@ -1,98 +1,145 @@
 package game
 
 import (
-	"goinvaders/internal/assets"
-	"goinvaders/internal/assets/sounds"
-	"goinvaders/internal/tools"
-
-	rl "github.com/gen2brain/raylib-go/raylib"
+	"goinvaders/internal/assets/atlas"
+	"goinvaders/internal/effects"
 )
 
+// Speed of the spaceship in pixels per second
+const spaceshipSpeed float32 = 420
+
+// Tint of each spaceship, so that co-op players can tell theirs apart
+var ShipTints = [MaxShips]effects.Color{
+	white,
+	{R: 120, G: 200, B: 255, A: 255},
+}
+
 type Spaceship struct {
-	image        rl.Texture2D
-	position     rl.Vector2
-	lasers       []*Laser
+	position     Vector2
+	previous     Vector2
+	size         Vector2
 	lastFireTime float64
-	laserSound   rl.Sound
-	mute         bool
-}
-
-func NewSpaceship() Spaceship {
-	image := assets.GetSpaceshipImage()
-	xpos := float32(rl.GetScreenWidth()-int(image.Width)) / 2
-	ypos := float32(rl.GetScreenHeight()-int(image.Height)) - 100
-	return Spaceship{
-		image:        image,
-		position:     rl.Vector2{X: xpos, Y: ypos},
-		lasers:       make([]*Laser, 0),
+	alive        bool
+	// the player has no lives left, the spaceship does not come back
+	out bool
+	// game time at which the spaceship comes back after exploding
+	respawnTime float64
+	// game time until which alien lasers go through the spaceship
+	invulnerableUntil float64
+	// as of the last update: the spaceship cannot be hit,
+	// and it is in the hidden part of its blinking
+	shielded bool
+	hidden   bool
+	// index of the spaceship, which is also its player in co-op games
+	player int
+}
+
+func NewSpaceship(worldWidth, worldHeight float32, player int) *Spaceship {
+	s := &Spaceship{
+		player:       player,
+		size:         spriteSize(atlas.SpaceshipSprite),
 		lastFireTime: 0,
-		laserSound:   assets.LoadSound(sounds.Laser_ogg),
-		mute:         false,
 	}
+	s.Reset(worldWidth, worldHeight)
+	return s
 }
 
-func (s *Spaceship) GetRect() rl.Rectangle {
-	return rl.Rectangle{
-		X:      s.position.X,
-		Y:      s.position.Y,
-		Width:  float32(s.image.Width),
-		Height: float32(s.image.Height),
-	}
+func (s *Spaceship) InterpolatedPosition(alpha float32) Vector2 {
+	return Lerp(s.previous, s.position, alpha)
 }
 
-func (s *Spaceship) Reset() {
-	s.position.X = float32(rl.GetScreenWidth()-int(s.image.Width)) / 2
-	s.position.Y = float32(rl.GetScreenHeight()) - float32(s.image.Height) - 100
-	s.lasers = make([]*Laser, 0)
+func (s *Spaceship) savePosition() {
+	s.previous = s.position
 }
 
-func (s *Spaceship) FireLaser() {
-	if rl.GetTime()-s.lastFireTime >= 0.35 {
-		if !s.mute {
-			rl.PlaySound(s.laserSound)
-		}
-		posx := int32(s.position.X) + s.image.Width/2 - 2
-		posy := int32(s.position.Y)
-		s.lasers = append(s.lasers, NewLaser(posx, posy, -6))
-		s.lastFireTime = rl.GetTime()
-	}
+// The spaceship leaves the world when its player is out of the game
+func (s *Spaceship) Alive() bool {
+	return !s.out
+}
+
+func (s *Spaceship) Layer() Layer {
+	return ShipLayer
+}
+
+// Index of the spaceship, 0 for the first one
+func (s *Spaceship) Player() int {
+	return s.player
 }
 
-func (s *Spaceship) Update() {
-	// delete inactive lasers
-	s.lasers = tools.FilterSlice(s.lasers,
-		func(laser *Laser) bool {
-			return laser.active
-		})
+func (s *Spaceship) Invulnerable(now float64) bool {
+	return now < s.invulnerableUntil
+}
 
-	for _, laser := range s.lasers {
-		laser.Update()
+func (s *Spaceship) GetRect() Rectangle {
+	return Rectangle{
+		X:      s.position.X,
+		Y:      s.position.Y,
+		Width:  s.size.X,
+		Height: s.size.Y,
 	}
 }
 
-func (s *Spaceship) Draw() {
-	rl.DrawTextureV(s.image, s.position, rl.White)
+func (s *Spaceship) Reset(worldWidth, worldHeight float32) {
+	s.position.X = (worldWidth - s.size.X) / 2
+	s.position.Y = worldHeight - s.size.Y - 100
+	s.previous = s.position
+	s.lastFireTime = 0
+	s.alive = true
+	s.out = false
+	s.invulnerableUntil = 0
+	s.shielded = false
+	s.hidden = false
+}
 
-	for _, laser := range s.lasers {
-		laser.Draw()
+// Fires a laser if enough time has passed since the last one.
+// Returns the laser fired, or nil.
+func (s *Spaceship) FireLaser(now float64) *Laser {
+	if now-s.lastFireTime < 0.35 {
+		return nil
 	}
+	posx := int32(s.position.X) + int32(s.size.X)/2 - 2
+	posy := int32(s.position.Y)
+	laser := NewLaser(posx, posy, -laserSpeed)
+	laser.ship = s
+	s.lastFireTime = now
+	return laser
+}
+
+// The spaceship moves with the input of its player, here it only
+// keeps track of its blinking while it cannot be hit
+func (s *Spaceship) Update(ctx *Context) {
+	now := ctx.Game.time
+	s.shielded = s.Invulnerable(now)
+	blinks := int((s.invulnerableUntil - now) / ctx.Game.params.Death.BlinkInterval)
+	s.hidden = s.shielded && blinks%2 != 0
 }
 
-func (s *Spaceship) DrawAt(xpos, ypos int32) {
-	position := rl.Vector2{X: float32(xpos), Y: float32(ypos)}
-	rl.DrawTextureV(s.image, position, rl.White)
+// The spaceship is hidden while exploding, and blinks while it cannot be hit
+func (s *Spaceship) Draw(r Renderer) {
+	if !s.alive {
+		return
+	}
+	tint := ShipTints[s.player]
+	switch {
+	case s.shielded && r.ReduceFlashing():
+		// see through instead of blinking
+		tint.A = uint8(float32(tint.A) * 0.4)
+	case s.hidden:
+		return
+	}
+	r.DrawSprite(atlas.SpaceshipSprite, 0, s.InterpolatedPosition(r.Alpha()), tint)
 }
 
 func (s *Spaceship) MoveLeft() {
-	s.position.X -= 7
+	s.position.X -= perTick(spaceshipSpeed)
 	if s.position.X < 25 {
 		s.position.X = 25
 	}
 }
 
-func (s *Spaceship) MoveRight() {
-	s.position.X += 7
-	maxpos := float32(rl.GetScreenWidth()-int(s.image.Width)) - 25
+func (s *Spaceship) MoveRight(worldWidth float32) {
+	s.position.X += perTick(spaceshipSpeed)
+	maxpos := worldWidth - s.size.X - 25
 	if s.position.X > maxpos {
 		s.position.X = maxpos
 	}
@@ -0,0 +1,27 @@
+package game
+
+import (
+	"goinvaders/internal/assets/atlas"
+	"goinvaders/internal/assets/images"
+	"goinvaders/internal/effects"
+)
+
+// The sprite sheet is only used to know the size of each entity,
+// textures are loaded and drawn by the presentation layer.
+var sprites = atlas.MustParse(images.Ships_xml)
+
+func spriteSize(name string) Vector2 {
+	w, h := sprites.Size(name)
+	return Vector2{X: w, Y: h}
+}
+
+// Number of frames of the animation
+func spriteFrames(name string) int {
+	return len(sprites.Animations[name])
+}
+
+// Colors of the entities drawn without a sprite, and tint of the others
+var (
+	yellow = effects.Color{R: 243, G: 216, B: 63, A: 255}
+	white  = effects.Color{R: 255, G: 255, B: 255, A: 255}
+)
@@ -0,0 +1,76 @@
+package input
+
+// Action is something the player wants to do, whatever the device used
+type Action int
+
+const (
+	MoveLeft Action = iota
+	MoveRight
+	Fire
+	Pause
+	ToggleMusic
+	ToggleSfx
+	Confirm
+	Back
+	MenuUp
+	MenuDown
+	// The spaceship of the second player, in co-op games
+	MoveLeft2
+	MoveRight2
+	Fire2
+	actionCount
+)
+
+var actionNames = [actionCount]string{
+	MoveLeft:    "MoveLeft",
+	MoveRight:   "MoveRight",
+	Fire:        "Fire",
+	Pause:       "Pause",
+	ToggleMusic: "ToggleMusic",
+	ToggleSfx:   "ToggleSfx",
+	Confirm:     "Confirm",
+	Back:        "Back",
+	MenuUp:      "MenuUp",
+	MenuDown:    "MenuDown",
+	MoveLeft2:   "MoveLeft2",
+	MoveRight2:  "MoveRight2",
+	Fire2:       "Fire2",
+}
+
+// The actions of the second player, by the matching action of the first one
+var secondPlayerActions = map[Action]Action{
+	MoveLeft:  MoveLeft2,
+	MoveRight: MoveRight2,
+	Fire:      Fire2,
+}
+
+// Returns the action of the second player matching the action, if there is one
+func SecondPlayerAction(action Action) (Action, bool) {
+	second, ok := secondPlayerActions[action]
+	return second, ok
+}
+
+func (a Action) String() string {
+	if a < 0 || a >= actionCount {
+		return "Unknown"
+	}
+	return actionNames[a]
+}
+
+func ActionByName(name string) (Action, bool) {
+	for action, actionName := range actionNames {
+		if actionName == name {
+			return Action(action), true
+		}
+	}
+	return 0, false
+}
+
+// All the actions, in declaration order
+func Actions() []Action {
+	actions := make([]Action, actionCount)
+	for i := range actions {
+		actions[i] = Action(i)
+	}
+	return actions
+}
@@ -0,0 +1,86 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Bindings map each action to the keys and gamepad buttons that trigger it.
+// The left analog stick always drives MoveLeft, MoveRight, MenuUp and MenuDown.
+// The second player has keys of their own, and plays with the buttons
+// of the first player on the second gamepad.
+type Bindings struct {
+	Keys    map[Action][]int32
+	Buttons map[Action][]int32
+}
+
+func DefaultBindings() Bindings {
+	return Bindings{
+		Keys: map[Action][]int32{
+			MoveLeft:    {rl.KeyLeft},
+			MoveRight:   {rl.KeyRight},
+			Fire:        {rl.KeySpace},
+			Pause:       {rl.KeyP},
+			ToggleMusic: {rl.KeyM},
+			ToggleSfx:   {rl.KeyS},
+			Confirm:     {rl.KeyEnter},
+			Back:        {rl.KeyEscape, rl.KeyBackspace},
+			MenuUp:      {rl.KeyUp},
+			MenuDown:    {rl.KeyDown},
+			MoveLeft2:   {rl.KeyA},
+			MoveRight2:  {rl.KeyD},
+			Fire2:       {rl.KeyW},
+		},
+		Buttons: map[Action][]int32{
+			MoveLeft:  {rl.GamepadButtonLeftFaceLeft},
+			MoveRight: {rl.GamepadButtonLeftFaceRight},
+			Fire:      {rl.GamepadButtonRightFaceDown},
+			Pause:     {rl.GamepadButtonMiddleRight},
+			Confirm:   {rl.GamepadButtonRightFaceDown, rl.GamepadButtonMiddleRight},
+			Back:      {rl.GamepadButtonRightFaceRight},
+			MenuUp:    {rl.GamepadButtonLeftFaceUp},
+			MenuDown:  {rl.GamepadButtonLeftFaceDown},
+		},
+	}
+}
+
+// Returns a copy of the bindings with the keys of some actions replaced.
+// keys holds key names by action name, as saved in the config file.
+func (b Bindings) WithKeys(keys map[string][]string) (Bindings, error) {
+	result := Bindings{Keys: maps.Clone(b.Keys), Buttons: maps.Clone(b.Buttons)}
+	var errs []error
+	for actionName, keyNames := range keys {
+		action, ok := ActionByName(actionName)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown action %q", actionName))
+			continue
+		}
+		actionKeys := make([]int32, 0, len(keyNames))
+		for _, keyName := range keyNames {
+			key, ok := KeyByName(keyName)
+			if !ok {
+				errs = append(errs, fmt.Errorf("unknown key %q for %s", keyName, actionName))
+				continue
+			}
+			actionKeys = append(actionKeys, key)
+		}
+		result.Keys[action] = actionKeys
+	}
+	return result, errors.Join(errs...)
+}
+
+// The keys of every action by name, to be saved in the config file
+func (b Bindings) KeyNames() map[string][]string {
+	keys := make(map[string][]string)
+	for action, actionKeys := range b.Keys {
+		names := make([]string, 0, len(actionKeys))
+		for _, key := range actionKeys {
+			names = append(names, KeyName(key))
+		}
+		keys[action.String()] = names
+	}
+	return keys
+}
@@ -0,0 +1,139 @@
+package input
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Raylib supports up to 4 gamepads
+const maxGamepads = 4
+
+// How far the analog stick must be pushed before it counts as a move
+const stickDeadZone float32 = 0.35
+
+// Controls turns the keyboard and gamepad state into actions.
+// Update must be called once per frame, before asking for any action.
+type Controls struct {
+	bindings Bindings
+	gamepads [maxGamepads]bool
+	// the second gamepad plays the second player
+	split    bool
+	down     [actionCount]bool
+	previous [actionCount]bool
+}
+
+func NewControls(bindings Bindings) *Controls {
+	return &Controls{
+		bindings: bindings,
+	}
+}
+
+func (c *Controls) Bindings() Bindings {
+	return c.bindings
+}
+
+func (c *Controls) SetBindings(bindings Bindings) {
+	c.bindings = bindings
+}
+
+// Gives the second gamepad to the second player, for co-op games.
+// Otherwise all the gamepads play the first player.
+func (c *Controls) SetSplit(split bool) {
+	c.split = split
+}
+
+// Polls all the devices. Gamepads plugged in while the game runs are picked up here.
+func (c *Controls) Update() {
+	c.previous = c.down
+	c.detectGamepads()
+
+	for action := range c.down {
+		c.down[action] = c.keyDown(Action(action))
+	}
+
+	connected := 0
+	for gamepad, available := range c.gamepads {
+		if !available {
+			continue
+		}
+		second := c.split && connected == 1
+		connected++
+		// the actions of the player holding the gamepad
+		player := func(action Action) Action {
+			if second {
+				if other, ok := SecondPlayerAction(action); ok {
+					return other
+				}
+			}
+			return action
+		}
+
+		for action, buttons := range c.bindings.Buttons {
+			if buttonDown(gamepad, buttons) {
+				c.down[player(action)] = true
+			}
+		}
+		x := rl.GetGamepadAxisMovement(int32(gamepad), rl.GamepadAxisLeftX)
+		if x < -stickDeadZone {
+			c.down[player(MoveLeft)] = true
+		}
+		if x > stickDeadZone {
+			c.down[player(MoveRight)] = true
+		}
+		y := rl.GetGamepadAxisMovement(int32(gamepad), rl.GamepadAxisLeftY)
+		if y < -stickDeadZone {
+			c.down[MenuUp] = true
+		}
+		if y > stickDeadZone {
+			c.down[MenuDown] = true
+		}
+	}
+}
+
+func (c *Controls) detectGamepads() {
+	for gamepad := range c.gamepads {
+		connected := rl.IsGamepadAvailable(int32(gamepad))
+		if connected && !c.gamepads[gamepad] {
+			rl.TraceLog(rl.LogInfo, "Gamepad %d connected: %s", gamepad, rl.GetGamepadName(int32(gamepad)))
+		}
+		if !connected && c.gamepads[gamepad] {
+			rl.TraceLog(rl.LogInfo, "Gamepad %d disconnected", gamepad)
+		}
+		c.gamepads[gamepad] = connected
+	}
+}
+
+func (c *Controls) keyDown(action Action) bool {
+	for _, key := range c.bindings.Keys[action] {
+		if rl.IsKeyDown(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func buttonDown(gamepad int, buttons []int32) bool {
+	for _, button := range buttons {
+		if rl.IsGamepadButtonDown(int32(gamepad), button) {
+			return true
+		}
+	}
+	return false
+}
+
+// Is the action held down?
+func (c *Controls) Down(action Action) bool {
+	return c.down[action]
+}
+
+// Has the action just started this frame?
+func (c *Controls) Pressed(action Action) bool {
+	return c.down[action] && !c.previous[action]
+}
+
+// Has any action just started this frame?
+func (c *Controls) AnyPressed() bool {
+	for action := range c.down {
+		if c.Pressed(Action(action)) {
+			return true
+		}
+	}
+	return false
+}
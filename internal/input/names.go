@@ -0,0 +1,92 @@
+package input
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var keyNames = map[int32]string{
+	rl.KeyRightShift:   "RIGHT SHIFT",
+	rl.KeyRightControl: "RIGHT CTRL",
+	rl.KeyRightAlt:     "RIGHT ALT",
+	rl.KeyDelete:       "DELETE",
+	rl.KeyInsert:       "INSERT",
+	rl.KeyHome:         "HOME",
+	rl.KeyEnd:          "END",
+	rl.KeyPageUp:       "PAGE UP",
+	rl.KeyPageDown:     "PAGE DOWN",
+	rl.KeyComma:        ",",
+	rl.KeyPeriod:       ".",
+	rl.KeySlash:        "/",
+	rl.KeySemicolon:    ";",
+	rl.KeyMinus:        "-",
+	rl.KeyEqual:        "=",
+	rl.KeySpace:        "SPACE",
+	rl.KeyEscape:       "ESC",
+	rl.KeyEnter:        "ENTER",
+	rl.KeyTab:          "TAB",
+	rl.KeyBackspace:    "BACKSPACE",
+	rl.KeyRight:        "RIGHT",
+	rl.KeyLeft:         "LEFT",
+	rl.KeyDown:         "DOWN",
+	rl.KeyUp:           "UP",
+	rl.KeyLeftShift:    "SHIFT",
+	rl.KeyLeftControl:  "CTRL",
+	rl.KeyLeftAlt:      "ALT",
+}
+
+var buttonNames = map[int32]string{
+	rl.GamepadButtonLeftFaceUp:     "D-PAD UP",
+	rl.GamepadButtonLeftFaceRight:  "D-PAD RIGHT",
+	rl.GamepadButtonLeftFaceDown:   "D-PAD DOWN",
+	rl.GamepadButtonLeftFaceLeft:   "D-PAD LEFT",
+	rl.GamepadButtonRightFaceUp:    "Y",
+	rl.GamepadButtonRightFaceRight: "B",
+	rl.GamepadButtonRightFaceDown:  "A",
+	rl.GamepadButtonRightFaceLeft:  "X",
+	rl.GamepadButtonLeftTrigger1:   "LB",
+	rl.GamepadButtonRightTrigger1:  "RB",
+	rl.GamepadButtonMiddleLeft:     "SELECT",
+	rl.GamepadButtonMiddleRight:    "START",
+}
+
+// Name of a keyboard key, as shown to the player
+func KeyName(key int32) string {
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+	// letters and digits have their ascii code
+	if (key >= rl.KeyA && key <= rl.KeyZ) || (key >= rl.KeyZero && key <= rl.KeyNine) {
+		return string(rune(key))
+	}
+	return fmt.Sprintf("KEY %d", key)
+}
+
+// Finds the key with the given name, the reverse of KeyName
+func KeyByName(name string) (int32, bool) {
+	for key, keyName := range keyNames {
+		if keyName == name {
+			return key, true
+		}
+	}
+	var key int32
+	if _, err := fmt.Sscanf(name, "KEY %d", &key); err == nil {
+		return key, true
+	}
+	if len(name) == 1 {
+		key = int32(name[0])
+		if (key >= rl.KeyA && key <= rl.KeyZ) || (key >= rl.KeyZero && key <= rl.KeyNine) {
+			return key, true
+		}
+	}
+	return 0, false
+}
+
+// Name of a gamepad button, with the Xbox layout
+func ButtonName(button int32) string {
+	if name, ok := buttonNames[button]; ok {
+		return name
+	}
+	return fmt.Sprintf("BUTTON %d", button)
+}
@@ -0,0 +1,166 @@
+package scores
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goinvaders/internal/storage"
+	"goinvaders/internal/tools"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Number of entries kept in the high score table
+const MaxEntries = 10
+
+// Number of letters of the player initials, as in the arcade
+const InitialsLength = 3
+
+// Game modes stored with each entry
+const (
+	OnePlayer  = "1P"
+	TwoPlayers = "2P"
+	CoOp       = "CO"
+)
+
+const (
+	fileName       = "scores.json"
+	legacyFileName = "highscore.txt"
+)
+
+type Entry struct {
+	Initials string    `json:"initials"`
+	Score    int32     `json:"score"`
+	Level    int32     `json:"level"`
+	Date     time.Time `json:"date"`
+	Mode     string    `json:"mode"`
+}
+
+// Table is the high score table, best score first
+type Table struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Returns the best score in the table, 0 if it is empty
+func (t *Table) Best() int32 {
+	if len(t.Entries) == 0 {
+		return 0
+	}
+	return t.Entries[0].Score
+}
+
+// Does the score deserve a place in the table?
+func (t *Table) Qualifies(score int32) bool {
+	if score <= 0 {
+		return false
+	}
+	return len(t.Entries) < MaxEntries || score > t.Entries[len(t.Entries)-1].Score
+}
+
+// Inserts the entry at its place and returns its rank (0 is the best),
+// or -1 if the score is not good enough for the table.
+// On equal scores the older entry stays in front.
+func (t *Table) Add(entry Entry) int {
+	if !t.Qualifies(entry.Score) {
+		return -1
+	}
+	entry.Initials = strings.ToUpper(entry.Initials)
+	rank := sort.Search(len(t.Entries), func(i int) bool {
+		return t.Entries[i].Score < entry.Score
+	})
+	t.Entries = append(t.Entries, Entry{})
+	copy(t.Entries[rank+1:], t.Entries[rank:])
+	t.Entries[rank] = entry
+	if len(t.Entries) > MaxEntries {
+		t.Entries = t.Entries[:MaxEntries]
+	}
+	return rank
+}
+
+// Loads the high score table from the config folder.
+// A missing table is not an error: the old single high score file is
+// migrated if present, otherwise the table starts empty.
+// A damaged or edited table is backed up and reported, and an empty one
+// is returned in its place.
+func Load() (*Table, error) {
+	path, err := tools.GetConfigPath(fileName)
+	if err != nil {
+		return &Table{}, err
+	}
+
+	data, err := storage.ReadSealed(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return migrate()
+	}
+	if errors.Is(err, storage.ErrCorrupt) {
+		return &Table{}, storage.Quarantine(path, err)
+	}
+	if err != nil {
+		return &Table{}, err
+	}
+
+	table := &Table{}
+	if err := json.Unmarshal(data, table); err != nil {
+		return &Table{}, storage.Quarantine(path, fmt.Errorf("%s: %w", path, err))
+	}
+	sort.SliceStable(table.Entries, func(i, j int) bool {
+		return table.Entries[i].Score > table.Entries[j].Score
+	})
+	return table, nil
+}
+
+func (t *Table) Save() error {
+	path, err := tools.GetConfigPath(fileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteSealed(path, append(data, '\n'))
+}
+
+// Older versions only saved the best score in highscore.txt.
+// It becomes the first entry of the table, and the old file is renamed
+// so that the migration only happens once.
+func migrate() (*Table, error) {
+	table := &Table{}
+	path, err := tools.GetConfigPath(legacyFileName)
+	if err != nil {
+		return table, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return table, nil
+	}
+	if err != nil {
+		return table, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return table, err
+	}
+	var score int32
+	_, err = fmt.Fscanf(file, "%d", &score)
+	file.Close()
+	if err != nil {
+		return table, storage.Quarantine(path, fmt.Errorf("could not migrate %s: %w", path, err))
+	}
+
+	table.Add(Entry{
+		Initials: "???",
+		Score:    score,
+		Date:     info.ModTime(),
+		Mode:     OnePlayer,
+	})
+	if err := table.Save(); err != nil {
+		return table, err
+	}
+	return table, os.Rename(path, path+".migrated")
+}
@@ -0,0 +1,197 @@
+package scores
+
+import (
+	"errors"
+	"goinvaders/internal/storage"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Points the config folder to a new temporary one and returns it
+func configDir(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "goinvaders")
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func initials(t *Table) []string {
+	names := make([]string, len(t.Entries))
+	for i, entry := range t.Entries {
+		names[i] = entry.Initials
+	}
+	return names
+}
+
+func TestAdd(t *testing.T) {
+	full := &Table{}
+	for i := range MaxEntries {
+		full.Add(Entry{Initials: string(rune('a' + i)), Score: int32(1000 - 100*i)})
+	}
+
+	tests := []struct {
+		name     string
+		table    Table
+		entry    Entry
+		rank     int
+		initials []string
+	}{
+		{
+			name:     "first entry",
+			entry:    Entry{Initials: "abc", Score: 100},
+			rank:     0,
+			initials: []string{"ABC"},
+		},
+		{
+			name:     "best score goes in front",
+			table:    Table{Entries: []Entry{{Initials: "AAA", Score: 200}}},
+			entry:    Entry{Initials: "BBB", Score: 300},
+			rank:     0,
+			initials: []string{"BBB", "AAA"},
+		},
+		{
+			name:     "older entry stays in front on equal scores",
+			table:    Table{Entries: []Entry{{Initials: "AAA", Score: 200}, {Initials: "CCC", Score: 100}}},
+			entry:    Entry{Initials: "BBB", Score: 200},
+			rank:     1,
+			initials: []string{"AAA", "BBB", "CCC"},
+		},
+		{
+			name:     "no score, no entry",
+			entry:    Entry{Initials: "AAA", Score: 0},
+			rank:     -1,
+			initials: []string{},
+		},
+		{
+			name:     "the last entry drops out of a full table",
+			table:    *full,
+			entry:    Entry{Initials: "NEW", Score: 950},
+			rank:     1,
+			initials: []string{"A", "NEW", "B", "C", "D", "E", "F", "G", "H", "I"},
+		},
+		{
+			name:     "too low for a full table",
+			table:    *full,
+			entry:    Entry{Initials: "LOW", Score: 100},
+			rank:     -1,
+			initials: []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := Table{Entries: slices.Clone(tt.table.Entries)}
+			if rank := table.Add(tt.entry); rank != tt.rank {
+				t.Errorf("got rank %d, want %d", rank, tt.rank)
+			}
+			if got := initials(&table); !slices.Equal(got, tt.initials) {
+				t.Errorf("got table %v, want %v", got, tt.initials)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		// files written in the config folder before loading
+		files map[string]string
+		// sealed scores.json, when not nil
+		table   *Table
+		want    []int32
+		corrupt bool
+		// files expected in the config folder after loading, and not
+		exist []string
+		gone  []string
+	}{
+		{
+			name: "nothing saved yet",
+			want: []int32{},
+		},
+		{
+			name:  "migrates the old high score",
+			files: map[string]string{legacyFileName: "4200\n"},
+			want:  []int32{4200},
+			exist: []string{fileName, legacyFileName + ".migrated"},
+			gone:  []string{legacyFileName},
+		},
+		{
+			name:  "the table wins over the old high score",
+			files: map[string]string{legacyFileName: "4200\n"},
+			table: &Table{Entries: []Entry{{Initials: "AAA", Score: 100}}},
+			want:  []int32{100},
+		},
+		{
+			name:  "sorts the entries",
+			table: &Table{Entries: []Entry{{Score: 100}, {Score: 300}, {Score: 200}}},
+			want:  []int32{300, 200, 100},
+		},
+		{
+			name:    "edited table",
+			files:   map[string]string{fileName: "{\"entries\": [{\"score\": 999999}]}\n"},
+			want:    []int32{},
+			corrupt: true,
+			gone:    []string{fileName},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := configDir(t)
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0664); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.table != nil {
+				if err := tt.table.Save(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			table, err := Load()
+			if tt.corrupt != errors.Is(err, storage.ErrCorrupt) || !tt.corrupt && err != nil {
+				t.Errorf("got error %v", err)
+			}
+			got := make([]int32, len(table.Entries))
+			for i, entry := range table.Entries {
+				got[i] = entry.Score
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got scores %v, want %v", got, tt.want)
+			}
+			for _, name := range tt.exist {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("%s is missing: %v", name, err)
+				}
+			}
+			for _, name := range tt.gone {
+				if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+					t.Errorf("%s is still there", name)
+				}
+			}
+		})
+	}
+}
+
+// A migrated table is saved, so the next load finds the same one
+func TestLoadAfterMigration(t *testing.T) {
+	dir := configDir(t)
+	if err := os.WriteFile(filepath.Join(dir, legacyFileName), []byte("4200\n"), 0664); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err != nil {
+		t.Fatal(err)
+	}
+	table, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Best() != 4200 || table.Entries[0].Initials != "???" {
+		t.Errorf("got %+v, want the migrated score", table.Entries)
+	}
+}
@@ -0,0 +1,150 @@
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goinvaders/internal/game"
+	"goinvaders/internal/storage"
+	"goinvaders/internal/tools"
+	"io/fs"
+	"time"
+)
+
+const fileName = "achievements.json"
+
+type Achievement struct {
+	ID          string
+	Name        string
+	Description string
+	// tells if the event earns the achievement, given the stats
+	// of the game and of the level so far, the event included
+	earned func(event game.Event, total, level *Stats) bool
+}
+
+var Achievements = []Achievement{
+	{
+		ID: "first-blood", Name: "FIRST BLOOD", Description: "Destroy an alien",
+		earned: func(event game.Event, total, level *Stats) bool {
+			return total.AliensKilled >= 1
+		},
+	},
+	{
+		ID: "close-encounter", Name: "CLOSE ENCOUNTER", Description: "Destroy a mystery ship",
+		earned: func(event game.Event, total, level *Stats) bool {
+			return total.MysteryShipsKilled >= 1
+		},
+	},
+	{
+		ID: "wave-breaker", Name: "WAVE BREAKER", Description: "Clear a level",
+		earned: func(event game.Event, total, level *Stats) bool {
+			return total.LevelsCleared >= 1
+		},
+	},
+	{
+		ID: "untouchable", Name: "UNTOUCHABLE", Description: "Clear a level without losing a life",
+		earned: func(event game.Event, total, level *Stats) bool {
+			return level.LevelsCleared >= 1 && level.LivesLost == 0
+		},
+	},
+	{
+		ID: "sharpshooter", Name: "SHARPSHOOTER", Description: "Clear a level hitting with 3 lasers out of 4",
+		earned: func(event game.Event, total, level *Stats) bool {
+			return level.LevelsCleared >= 1 && level.Accuracy() >= 0.75
+		},
+	},
+	{
+		ID: "centurion", Name: "CENTURION", Description: "Destroy 100 aliens in a game",
+		earned: func(event game.Event, total, level *Stats) bool {
+			return total.AliensKilled >= 100
+		},
+	},
+	{
+		ID: "veteran", Name: "VETERAN", Description: "Clear level 5",
+		earned: func(event game.Event, total, level *Stats) bool {
+			cleared, ok := event.(game.LevelCleared)
+			return ok && cleared.Level >= 5
+		},
+	},
+}
+
+// Tracker keeps the stats of the game being played and unlocks
+// the achievements as they are earned
+type Tracker struct {
+	// Stats of the whole game, and of the current level
+	Game  Stats `json:"-"`
+	Level Stats `json:"-"`
+	// When each achievement was unlocked, by ID
+	Unlocked map[string]time.Time `json:"unlocked"`
+	// Called for each achievement unlocked
+	OnUnlock func(achievement Achievement) `json:"-"`
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		Unlocked: make(map[string]time.Time),
+	}
+}
+
+// Counts the event, then unlocks the achievements it earns.
+// Subscribe it to the events of the game with Bus.SubscribeAll.
+func (t *Tracker) Handle(event game.Event) {
+	t.Game.Handle(event)
+	t.Level.Handle(event)
+	for _, achievement := range Achievements {
+		if _, done := t.Unlocked[achievement.ID]; done {
+			continue
+		}
+		if achievement.earned(event, &t.Game, &t.Level) {
+			t.Unlocked[achievement.ID] = time.Now()
+			if t.OnUnlock != nil {
+				t.OnUnlock(achievement)
+			}
+		}
+	}
+	if _, ok := event.(game.LevelCleared); ok {
+		t.Level = Stats{}
+	}
+}
+
+// Loads the achievements unlocked so far from the config folder.
+// None are unlocked when the file is missing. A damaged or edited file is
+// backed up and reported, and the achievements start over.
+func Load() (*Tracker, error) {
+	path, err := tools.GetConfigPath(fileName)
+	if err != nil {
+		return NewTracker(), err
+	}
+
+	data, err := storage.ReadSealed(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewTracker(), nil
+	}
+	if errors.Is(err, storage.ErrCorrupt) {
+		return NewTracker(), storage.Quarantine(path, err)
+	}
+	if err != nil {
+		return NewTracker(), err
+	}
+
+	tracker := NewTracker()
+	if err := json.Unmarshal(data, tracker); err != nil {
+		return NewTracker(), storage.Quarantine(path, fmt.Errorf("%s: %w", path, err))
+	}
+	if tracker.Unlocked == nil {
+		tracker.Unlocked = make(map[string]time.Time)
+	}
+	return tracker, nil
+}
+
+func (t *Tracker) Save() error {
+	path, err := tools.GetConfigPath(fileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return storage.WriteSealed(path, append(data, '\n'))
+}
@@ -0,0 +1,42 @@
+package stats
+
+import "goinvaders/internal/game"
+
+// Stats counts what the players did, from the events of the game
+type Stats struct {
+	LasersFired        int
+	AliensKilled       int
+	MysteryShipsKilled int
+	LivesLost          int
+	LevelsCleared      int
+	BunkerHits         int
+}
+
+// Counts the event. A new game starts counting from zero.
+func (s *Stats) Handle(event game.Event) {
+	switch event.(type) {
+	case game.GameStarted:
+		*s = Stats{}
+	case game.LaserFired:
+		s.LasersFired++
+	case game.AlienKilled:
+		s.AliensKilled++
+	case game.MysteryShipKilled:
+		s.MysteryShipsKilled++
+	case game.PlayerHit:
+		s.LivesLost++
+	case game.LevelCleared:
+		s.LevelsCleared++
+	case game.BunkerDamaged:
+		s.BunkerHits++
+	}
+}
+
+// Lasers that destroyed an alien or a mystery ship, out of all the
+// lasers fired, from 0 to 1
+func (s *Stats) Accuracy() float64 {
+	if s.LasersFired == 0 {
+		return 0
+	}
+	return float64(s.AliensKilled+s.MysteryShipsKilled) / float64(s.LasersFired)
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrCorrupt is returned when a sealed file does not match its signature,
+// either because it was damaged or because it was edited by hand
+var ErrCorrupt = errors.New("file is corrupt or was modified")
+
+// Sealed files start with this tag followed by the hex HMAC of the content.
+// The key is not a secret (the source is public): the goal is to detect
+// damaged or edited files, not to stop a determined cheater.
+const sealTag = "GOINVADERS-HMAC-SHA256 "
+
+var sealKey = []byte("goinvaders: you shall not edit the high scores")
+
+func signature(data []byte) []byte {
+	mac := hmac.New(sha256.New, sealKey)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Returns data preceded by a header line holding its signature
+func Seal(data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(sealTag)
+	buf.WriteString(hex.EncodeToString(signature(data)))
+	buf.WriteByte('\n')
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// Checks the signature of sealed data and returns the original content
+func Unseal(sealed []byte) ([]byte, error) {
+	header, data, found := bytes.Cut(sealed, []byte("\n"))
+	if !found || !bytes.HasPrefix(header, []byte(sealTag)) {
+		return nil, fmt.Errorf("%w: missing signature", ErrCorrupt)
+	}
+	sum, err := hex.DecodeString(string(header[len(sealTag):]))
+	if err != nil || !hmac.Equal(sum, signature(data)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrCorrupt)
+	}
+	return data, nil
+}
+
+// Writes a file so that a crash leaves either the old or the new content,
+// never a partial one: the data goes to a temporary file in the same folder,
+// which is flushed to disk and then renamed over the real one.
+func WriteFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// harmless once the rename succeeded
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0664); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// make the rename itself durable; not every platform supports this
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// Atomically writes data with its signature
+func WriteSealed(path string, data []byte) error {
+	return WriteFile(path, Seal(data))
+}
+
+// Reads a file written by WriteSealed.
+// The error wraps ErrCorrupt if the signature does not match.
+func ReadSealed(path string) ([]byte, error) {
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := Unseal(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
+
+// Moves a bad file out of the way, so that the game can start afresh
+// without losing it, and returns cause together with the backup name
+func Quarantine(path string, cause error) error {
+	backup := fmt.Sprintf("%s.bad-%s", path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(path, backup); err != nil {
+		return fmt.Errorf("%w (could not back it up: %s)", cause, err)
+	}
+	return fmt.Errorf("%w (backed up to %s)", cause, backup)
+}
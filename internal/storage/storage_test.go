@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnseal(t *testing.T) {
+	data := []byte("AAA 1200\nBBB 900\n")
+	sealed := Seal(data)
+	tests := []struct {
+		name    string
+		sealed  []byte
+		corrupt bool
+	}{
+		{name: "untouched", sealed: sealed},
+		{name: "content edited", sealed: bytes.Replace(sealed, []byte("900"), []byte("990"), 1), corrupt: true},
+		{name: "signature edited", sealed: append([]byte(sealTag+"00"), sealed[len(sealTag)+2:]...), corrupt: true},
+		{name: "truncated", sealed: sealed[:len(sealed)-4], corrupt: true},
+		{name: "no signature", sealed: data, corrupt: true},
+		{name: "empty", sealed: nil, corrupt: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unseal(tt.sealed)
+			if tt.corrupt {
+				if !errors.Is(err, ErrCorrupt) {
+					t.Errorf("got error %v, want %v", err, ErrCorrupt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("got %q, want %q", got, data)
+			}
+		})
+	}
+}
+
+func TestReadSealed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.txt")
+	data := []byte("AAA 1200\n")
+	if err := WriteSealed(path, data); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadSealed(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+
+	// nothing is left behind by the atomic write
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want only %s", len(entries), path)
+	}
+
+	sealed, _ := os.ReadFile(path)
+	os.WriteFile(path, bytes.Replace(sealed, []byte("1200"), []byte("9999"), 1), 0664)
+	_, err = ReadSealed(path)
+	if !errors.Is(err, ErrCorrupt) || !strings.Contains(err.Error(), path) {
+		t.Errorf("got error %v, want %v naming %s", err, ErrCorrupt, path)
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scores.txt")
+	content := []byte("edited")
+	if err := os.WriteFile(path, content, 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Quarantine(path, ErrCorrupt)
+	if !errors.Is(err, ErrCorrupt) {
+		t.Errorf("got error %v, want it to wrap %v", err, ErrCorrupt)
+	}
+	if _, statErr := os.Stat(path); !errors.Is(statErr, os.ErrNotExist) {
+		t.Errorf("%s is still there", path)
+	}
+	backups, _ := filepath.Glob(path + ".bad-*")
+	if len(backups) != 1 {
+		t.Fatalf("got backups %v, want one", backups)
+	}
+	if !strings.Contains(err.Error(), backups[0]) {
+		t.Errorf("got error %v, want it to name %s", err, backups[0])
+	}
+	if got, _ := os.ReadFile(backups[0]); !bytes.Equal(got, content) {
+		t.Errorf("got backup %q, want %q", got, content)
+	}
+
+	// a file that cannot be moved is reported with the cause
+	err = Quarantine(path, ErrCorrupt)
+	if !errors.Is(err, ErrCorrupt) || !strings.Contains(err.Error(), "could not back it up") {
+		t.Errorf("got error %v for a missing file", err)
+	}
+}
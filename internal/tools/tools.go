@@ -6,15 +6,6 @@ import (
 	"path/filepath"
 )
 
-func FilterSlice[T any](ss []T, test func(T) bool) (ret []T) {
-	for _, s := range ss {
-		if test(s) {
-			ret = append(ret, s)
-		}
-	}
-	return
-}
-
 func GetConfigPath(filename string) (string, error) {
 	cfgDir, err := os.UserHomeDir()
 	if err != nil {
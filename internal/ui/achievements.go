@@ -0,0 +1,29 @@
+package ui
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// Seconds each achievement unlocked stays on screen
+const toastDuration float32 = 3
+
+// Shows the achievements unlocked one after the other
+func (a *App) UpdateToasts(frameTime float32) {
+	if len(a.toasts) == 0 {
+		return
+	}
+	a.toastTime += frameTime
+	if a.toastTime >= toastDuration {
+		a.toasts = a.toasts[1:]
+		a.toastTime = 0
+	}
+}
+
+// The achievement unlocked is shown above the HUD, over the game
+func (a *App) ToastDraw() {
+	if len(a.toasts) == 0 {
+		return
+	}
+	rec := rl.Rectangle{X: 150, Y: 670, Width: 500, Height: 50}
+	rl.DrawRectangleRec(rec, grey)
+	rl.DrawRectangleLinesEx(rec, 2, yellow)
+	a.CenterTextAt(int(rec.X), int(rec.Y)+8, int(rec.Width), "UNLOCKED: %s", a.toasts[0])
+}
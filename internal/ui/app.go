@@ -0,0 +1,447 @@
+package ui
+
+import (
+	"goinvaders/internal/assets"
+	"goinvaders/internal/assets/fonts"
+	"goinvaders/internal/assets/sounds"
+	"goinvaders/internal/audio"
+	"goinvaders/internal/config"
+	"goinvaders/internal/game"
+	"goinvaders/internal/input"
+	"goinvaders/internal/scores"
+	"goinvaders/internal/stats"
+	"image/color"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var (
+	grey   = color.RGBA{R: 29, G: 29, B: 27, A: 255}
+	yellow = color.RGBA{R: 243, G: 216, B: 63, A: 255}
+	green  = color.RGBA{R: 11, G: 102, B: 35, A: 255}
+	red    = color.RGBA{R: 163, G: 22, B: 3, A: 255}
+)
+
+// Size of the game world. It is drawn to a texture of this size,
+// which is then scaled to fit the window.
+const (
+	worldWidth  = game.WorldWidth
+	worldHeight = game.WorldHeight
+)
+
+// Screens shown on top of the game world
+type screen int
+
+const (
+	playScreen screen = iota
+	titleScreen
+	demoScreen
+	initialsScreen
+	scoresScreen
+	pauseScreen
+	settingsScreen
+	keysScreen
+	controlsScreen
+)
+
+// Longest frame time taken into account: after a longer stall the game
+// slows down instead of running hundreds of ticks to catch up
+const maxFrameTime float32 = 0.25
+
+// App is the raylib front end of the game.
+// It reads the keyboard, plays sounds and draws the state of the simulation,
+// while all the game logic lives in the game package.
+type App struct {
+	game           *game.Game
+	config         config.Config
+	levels         game.LevelSet
+	target         rl.RenderTexture2D
+	screen         screen
+	scores         *scores.Table
+	entry          initialsEntry
+	pendingPlayers []int
+	titleMenu      *menu
+	pauseMenu      *menu
+	settingsMenu   *menu
+	keysMenu       *menu
+	rebinding      bool
+	rebindAction   input.Action
+	returnScreen   screen
+	screenTime     float32
+	attract        bool
+	demo           bool
+	titleSeed      uint64
+	newRank        int
+	controls       *input.Controls
+	inputs         game.TickInput
+	accumulator    float64
+	alpha          float32
+	recording      *game.Replay
+	playback       *game.Replay
+	playbackTick   int
+	font           rl.Font
+	animations     map[string]assets.Animation
+	audio          *audio.Manager
+	achievements   *stats.Tracker
+	// names of the achievements just unlocked, shown one after the other
+	toasts    []string
+	toastTime float32
+}
+
+// Options are the settings given on the command line
+type Options struct {
+	// User preferences, with the command line overrides already applied
+	Config config.Config
+	// Seed of the random source, 0 picks one from the clock
+	Seed uint64
+	// Levels to play
+	Levels game.LevelSet
+	// Keyboard and gamepad bindings
+	Bindings input.Bindings
+	// Plays the sounds, or nothing without an audio device
+	Audio audio.Backend
+	// Replay to watch instead of playing, the seed is then ignored
+	Replay *game.Replay
+}
+
+func New(opts Options) *App {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = game.NewSeed()
+	}
+
+	app := &App{
+		config:     opts.Config,
+		levels:     opts.Levels,
+		target:     rl.LoadRenderTexture(worldWidth, worldHeight),
+		playback:   opts.Replay,
+		screen:     playScreen,
+		newRank:    -1,
+		controls:   input.NewControls(opts.Bindings),
+		font:       assets.LoadFont(fonts.Monogram_ttf),
+		animations: make(map[string]assets.Animation),
+		audio:      audio.NewManager(opts.Audio),
+	}
+	app.titleMenu = app.newTitleMenu()
+	app.settingsMenu = app.newSettingsMenu()
+
+	if app.playback != nil {
+		app.StartPlayback()
+	} else {
+		difficulty, _ := game.ParseDifficulty(app.config.Difficulty)
+		app.game = game.New(worldWidth, worldHeight, seed)
+		app.game.SetDifficulty(difficulty)
+		app.game.SetLevels(app.levels)
+		app.Subscribe(app.game)
+		app.LoadScores()
+		app.game.SetHighScore(app.scores.Best())
+		app.screen = titleScreen
+	}
+	app.pauseMenu = app.newPauseMenu()
+	app.LoadAchievements()
+
+	app.LoadAudio()
+	return app
+}
+
+// Loads the sounds and sets the buses from the config.
+// A sound that cannot be loaded is only missing, the game goes on.
+func (a *App) LoadAudio() {
+	a.audio.SetVolume(audio.Master, a.config.MasterVolume)
+	a.audio.SetVolume(audio.Music, a.config.MusicVolume)
+	a.audio.SetVolume(audio.Sfx, a.config.SfxVolume)
+	a.audio.SetMuted(audio.Music, a.config.MuteMusic)
+	a.audio.SetMuted(audio.Sfx, a.config.MuteSfx)
+
+	errs := []error{
+		a.audio.LoadMusic(audio.Theme, sounds.Music_ogg),
+		// explosions come in bursts, the laser is limited by the fire rate
+		a.audio.LoadSound(audio.Explosion, audio.Sfx, sounds.Explosion_ogg, 8),
+		a.audio.LoadSound(audio.Laser, audio.Sfx, sounds.Laser_ogg, 4),
+	}
+	for note, id := range audio.March {
+		errs = append(errs, a.audio.LoadSound(id, audio.Music, audio.MarchNote(note), 2))
+	}
+	for _, err := range errs {
+		if err != nil {
+			rl.TraceLog(rl.LogError, "Audio not ready: %s", err.Error())
+		}
+	}
+	a.ApplyMusic()
+}
+
+// Plays the music track, or leaves the music to the march of the fleet
+func (a *App) ApplyMusic() {
+	if a.config.Music == config.MarchMusic {
+		a.audio.StopMusic()
+	} else {
+		a.audio.PlayMusic(audio.Theme)
+	}
+}
+
+// Listens to the events of the game for the sounds and the achievements
+func (a *App) Subscribe(g *game.Game) {
+	events := g.Events()
+	game.Subscribe(events, func(game.LaserFired) {
+		a.PlaySound(audio.Laser)
+	})
+	game.Subscribe(events, func(game.AlienKilled) {
+		a.PlaySound(audio.Explosion)
+	})
+	game.Subscribe(events, func(game.MysteryShipKilled) {
+		a.PlaySound(audio.Explosion)
+	})
+	game.Subscribe(events, func(game.PlayerHit) {
+		a.PlaySound(audio.Explosion)
+	})
+	game.Subscribe(events, func(e game.FleetStepped) {
+		if a.config.Music == config.MarchMusic {
+			a.PlaySound(audio.March[e.Step%len(audio.March)])
+		}
+	})
+	events.SubscribeAll(func(e game.Event) {
+		// only the games actually played count
+		if !a.demo && a.playback == nil {
+			a.achievements.Handle(e)
+		}
+	})
+}
+
+func (a *App) PlaySound(id audio.SoundID) {
+	// the demo of the attract mode is silent, like on the arcade cabinets
+	if !a.demo {
+		a.audio.Play(id)
+	}
+}
+
+func (a *App) ShouldQuit() bool {
+	return a.game.State() == game.Quit || rl.WindowShouldClose()
+}
+
+func (a *App) HandleGameOverInput() {
+	if a.controls.Pressed(input.Back) {
+		a.ToTitle()
+	}
+	if a.controls.Pressed(input.Confirm) {
+		a.game.Restart()
+		a.StartRecording()
+	}
+}
+
+func (a *App) HandleLevelUpInput() {
+	if a.controls.Pressed(input.Confirm) {
+		a.game.NextLevel()
+	}
+}
+
+func (a *App) HandleInput() {
+	a.controls.Update()
+	a.inputs = game.TickInput{}
+	a.controls.SetSplit(a.game.Mode().Simultaneous())
+
+	switch a.screen {
+	case titleScreen:
+		a.HandleTitleInput()
+		return
+	case demoScreen:
+		a.HandleDemoInput()
+		return
+	case initialsScreen:
+		a.HandleInitialsInput()
+		return
+	case scoresScreen:
+		a.HandleScoresInput()
+		return
+	case pauseScreen:
+		if a.controls.Pressed(input.Pause) && !a.pauseMenu.confirming {
+			a.Resume()
+		} else {
+			a.HandleMenuInput(a.pauseMenu)
+		}
+		return
+	case settingsScreen:
+		a.HandleMenuInput(a.settingsMenu)
+		return
+	case keysScreen:
+		a.HandleKeysInput()
+		return
+	case controlsScreen:
+		a.HandleControlsInput()
+		return
+	}
+
+	if a.playback != nil {
+		a.HandlePlaybackInput()
+	} else if a.game.State() == game.GameOver {
+		a.HandleGameOverInput()
+		return
+	}
+
+	if a.game.State() == game.LevelUp {
+		a.HandleLevelUpInput()
+		return
+	}
+
+	// Handle movement and laser fire
+	if a.game.State() == game.Running && a.playback == nil {
+		a.inputs[0] = game.Input{
+			Left:  a.controls.Down(input.MoveLeft),
+			Right: a.controls.Down(input.MoveRight),
+			Fire:  a.controls.Down(input.Fire),
+		}
+		a.inputs[1] = game.Input{
+			Left:  a.controls.Down(input.MoveLeft2),
+			Right: a.controls.Down(input.MoveRight2),
+			Fire:  a.controls.Down(input.Fire2),
+		}
+	}
+
+	// Handle pause / resume. Replays have no menu, they are only paused.
+	switch {
+	case a.playback != nil:
+		if a.controls.Pressed(input.Pause) {
+			a.game.TogglePause()
+		}
+	case a.game.State() == game.Running:
+		if a.controls.Pressed(input.Pause) || a.controls.Pressed(input.Back) {
+			a.OpenPauseMenu()
+		}
+	}
+
+	if a.controls.Pressed(input.ToggleMusic) {
+		a.ToggleMusic()
+	}
+	if a.controls.Pressed(input.ToggleSfx) {
+		a.ToggleSfx()
+	}
+}
+
+func (a *App) ToggleMusic() {
+	muted := !a.audio.Muted(audio.Music)
+	a.audio.SetMuted(audio.Music, muted)
+	a.SaveConfig(func(cfg *config.Config) {
+		cfg.MuteMusic = muted
+	})
+}
+
+func (a *App) ToggleSfx() {
+	muted := !a.audio.Muted(audio.Sfx)
+	a.audio.SetMuted(audio.Sfx, muted)
+	a.SaveConfig(func(cfg *config.Config) {
+		cfg.MuteSfx = muted
+	})
+}
+
+// Runs as many fixed simulation ticks as fit in the elapsed frame time.
+// What is left over is used to interpolate the drawing between two ticks.
+func (a *App) Update(frameTime float32) {
+	state := a.game.State()
+	if state == game.Running {
+		a.audio.Update()
+	}
+
+	a.accumulator += float64(min(frameTime, maxFrameTime))
+	for a.accumulator >= game.TickDuration {
+		a.Step()
+		a.accumulator -= game.TickDuration
+	}
+	// Without ticks the world is drawn where the last tick left it:
+	// interpolating towards a tick that never comes would make it shake
+	if a.game.State() == game.Running && (a.playback == nil || !a.PlaybackEnded()) {
+		a.alpha = float32(a.accumulator / game.TickDuration)
+	} else {
+		a.alpha = 1
+	}
+	a.UpdateAttract(frameTime)
+	a.UpdateToasts(frameTime)
+
+	if state != game.GameOver && a.game.State() == game.GameOver && a.playback == nil && !a.demo {
+		a.StopRecording()
+		a.EnterHighScore()
+		rl.TraceLog(rl.LogInfo, "Game Over!")
+	}
+}
+
+// Called once the main loop is over
+func (a *App) Close() {
+	a.StopRecording()
+}
+
+// Draws the world to its texture, then scales it to fit the window
+func (a *App) Draw() {
+	rl.BeginTextureMode(a.target)
+	a.DrawWorld()
+	rl.EndTextureMode()
+
+	screenWidth := float32(rl.GetScreenWidth())
+	screenHeight := float32(rl.GetScreenHeight())
+	scale := min(screenWidth/worldWidth, screenHeight/worldHeight)
+	dest := rl.Rectangle{
+		X:      (screenWidth - worldWidth*scale) / 2,
+		Y:      (screenHeight - worldHeight*scale) / 2,
+		Width:  worldWidth * scale,
+		Height: worldHeight * scale,
+	}
+	// render textures are upside down, hence the negative height
+	source := rl.Rectangle{X: 0, Y: 0, Width: worldWidth, Height: -worldHeight}
+
+	rl.ClearBackground(rl.Black)
+	rl.DrawTexturePro(a.target.Texture, source, dest, rl.Vector2{}, 0, rl.White)
+}
+
+func (a *App) DrawWorld() {
+	g := a.game
+	rl.ClearBackground(grey)
+
+	g.World().Draw(a)
+
+	// Effects go over the world but under the HUD
+	if !a.config.ReduceMotion {
+		particles := g.Particles()
+		for i := range particles {
+			a.DrawParticle(&particles[i])
+		}
+	}
+
+	// Draw the GUI
+	rl.DrawRectangleRoundedLines(rl.Rectangle{X: 10, Y: 10, Width: 780, Height: 780}, 0.18, 20, 2, yellow)
+	rl.DrawLineEx(rl.Vector2{X: 25, Y: 730}, rl.Vector2{X: 775, Y: 730}, 3, yellow)
+	if g.State() == game.GameOver {
+		a.TextAt(570, 740, "GAME OVER")
+	} else {
+		a.TextAt(570, 740, "LEVEL %02d", g.Level())
+	}
+	a.LivesDraw()
+	a.ScoresHUDDraw()
+
+	if a.playback != nil {
+		a.TextAt(300, 740, "REPLAY")
+		if a.PlaybackEnded() {
+			a.ReplayEndDraw()
+		}
+	} else {
+		switch a.screen {
+		case initialsScreen:
+			a.InitialsDraw()
+		case scoresScreen:
+			a.ScoresDraw(a.scoresFooter())
+		case titleScreen:
+			a.TitleDraw()
+		case demoScreen:
+			a.TextAt(300, 740, "DEMO")
+		default:
+			if g.State() == game.GameOver {
+				a.GameOverDraw()
+			}
+		}
+	}
+
+	if g.State() == game.LevelUp {
+		a.LevelUpDraw()
+	}
+	if g.TurnStarting() && g.Mode() == game.TwoPlayers {
+		a.TurnDraw()
+	}
+	a.PauseDraw()
+	a.ToastDraw()
+}
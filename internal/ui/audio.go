@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"goinvaders/internal/assets"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// RaylibAudio is the backend playing on the audio device opened by rl.InitAudioDevice.
+// The voices of a sound are aliases sharing the samples of the first one.
+type RaylibAudio struct {
+	sounds [][]rl.Sound
+	music  []rl.Music
+}
+
+func (r *RaylibAudio) LoadSound(data []byte, voices int) (int, error) {
+	// the sound files are ogg, the synthesized sounds are wav
+	fileType := ".ogg"
+	if bytes.HasPrefix(data, []byte("RIFF")) {
+		fileType = ".wav"
+	}
+	wave := rl.LoadWaveFromMemory(fileType, data, int32(len(data)))
+	sound := rl.LoadSoundFromWave(wave)
+	rl.UnloadWave(wave)
+	if !rl.IsSoundReady(sound) {
+		return 0, errors.New("could not be loaded")
+	}
+	aliases := []rl.Sound{sound}
+	for range voices - 1 {
+		aliases = append(aliases, rl.LoadSoundAlias(sound))
+	}
+	r.sounds = append(r.sounds, aliases)
+	return len(r.sounds) - 1, nil
+}
+
+func (r *RaylibAudio) PlayVoice(sound, voice int, volume float32) {
+	rl.SetSoundVolume(r.sounds[sound][voice], volume)
+	rl.PlaySound(r.sounds[sound][voice])
+}
+
+func (r *RaylibAudio) VoicePlaying(sound, voice int) bool {
+	return rl.IsSoundPlaying(r.sounds[sound][voice])
+}
+
+func (r *RaylibAudio) LoadMusic(data []byte) (int, error) {
+	music := assets.LoadMusic(data)
+	if !rl.IsMusicReady(music) {
+		return 0, errors.New("could not be loaded")
+	}
+	r.music = append(r.music, music)
+	return len(r.music) - 1, nil
+}
+
+func (r *RaylibAudio) PlayMusic(music int) {
+	rl.PlayMusicStream(r.music[music])
+}
+
+func (r *RaylibAudio) PauseMusic(music int) {
+	rl.PauseMusicStream(r.music[music])
+}
+
+func (r *RaylibAudio) ResumeMusic(music int) {
+	rl.ResumeMusicStream(r.music[music])
+}
+
+func (r *RaylibAudio) SetMusicVolume(music int, volume float32) {
+	rl.SetMusicVolume(r.music[music], volume)
+}
+
+func (r *RaylibAudio) UpdateMusic(music int) {
+	rl.UpdateMusicStream(r.music[music])
+}
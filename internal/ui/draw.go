@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"goinvaders/internal/assets"
+	"goinvaders/internal/effects"
+	"goinvaders/internal/game"
+	"image/color"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// The App is the renderer of the entities of the game world
+var _ game.Renderer = (*App)(nil)
+
+func vector(v game.Vector2) rl.Vector2 {
+	return rl.Vector2{X: v.X, Y: v.Y}
+}
+
+func rgba(c effects.Color) color.RGBA {
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
+
+// Returns the animation of the sprite sheet, loading it the first time
+func (a *App) animation(name string) assets.Animation {
+	animation, ok := a.animations[name]
+	if !ok {
+		animation = assets.LoadAnimation(name)
+		a.animations[name] = animation
+	}
+	return animation
+}
+
+func (a *App) Alpha() float32 {
+	return a.alpha
+}
+
+func (a *App) DrawSprite(animation string, step int, position game.Vector2, tint effects.Color) {
+	rl.DrawTextureV(a.animation(animation).Frame(max(step, 0)), vector(position), rgba(tint))
+}
+
+func (a *App) DrawRectangle(rect game.Rectangle, c effects.Color) {
+	rl.DrawRectangleRec(rl.Rectangle{X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height}, rgba(c))
+}
+
+func (a *App) ReduceFlashing() bool {
+	return a.config.ReduceFlashing
+}
+
+// Particles fade out as they get older
+func (a *App) DrawParticle(p *effects.Particle) {
+	tint := color.RGBA{R: p.Color.R, G: p.Color.G, B: p.Color.B, A: uint8(float32(p.Color.A) * p.Remaining())}
+	rl.DrawRectangleV(rl.Vector2{X: p.X - p.Size/2, Y: p.Y - p.Size/2}, rl.Vector2{X: p.Size, Y: p.Size}, tint)
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"goinvaders/internal/assets"
+	"goinvaders/internal/assets/atlas"
+	"goinvaders/internal/game"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func (a *App) TextAt(posx int, posy int, text string, args ...any) {
+	if len(args) > 0 {
+		text = fmt.Sprintf(text, args...)
+	}
+	rl.DrawTextEx(a.font, text, rl.Vector2{X: float32(posx), Y: float32(posy)}, 34, 2, assets.Yellow)
+}
+
+func (a *App) CenterTextAt(posx int, posy int, width int, text string, args ...any) {
+	if len(args) > 0 {
+		text = fmt.Sprintf(text, args...)
+	}
+	textWidth := int(rl.MeasureTextEx(a.font, text, 34, 2).X)
+	posx += (width - textWidth) / 2
+	rl.DrawTextEx(a.font, text, rl.Vector2{X: float32(posx), Y: float32(posy)}, 34, 2, assets.Yellow)
+}
+
+func (a *App) DrawDialogBox(text1, text2, text3 string, bkgcolor rl.Color) {
+	rwidth := 500
+	rheight := 200
+	rposx := (worldWidth - rwidth) / 2
+	rposy := 100
+
+	rec := rl.Rectangle{
+		X:      float32(rposx),
+		Y:      float32(rposy),
+		Width:  float32(rwidth),
+		Height: float32(rheight),
+	}
+
+	rl.DrawRectangleGradientH(int32(rposx), int32(rposy), int32(rwidth), int32(rheight), bkgcolor, bkgcolor)
+	rl.DrawRectangleLinesEx(rec, 10.0, yellow)
+	a.CenterTextAt(rposx, 150, rwidth, text1)
+	a.CenterTextAt(rposx, 190, rwidth, text2)
+	a.CenterTextAt(rposx, 230, rwidth, text3)
+}
+
+// Draws an empty box in the middle of the world and returns where it is
+func (a *App) DrawPanel(width, height int) rl.Rectangle {
+	rec := rl.Rectangle{
+		X:      float32((worldWidth - width) / 2),
+		Y:      float32((worldHeight - height) / 2),
+		Width:  float32(width),
+		Height: float32(height),
+	}
+	rl.DrawRectangleRec(rec, grey)
+	rl.DrawRectangleLinesEx(rec, 10.0, yellow)
+	return rec
+}
+
+// Draws a bar filled up to value, between 0 and 1, on a line of text
+func (a *App) DrawSlider(posx, posy, width int, value float32) {
+	rec := rl.Rectangle{X: float32(posx), Y: float32(posy + 8), Width: float32(width), Height: 20}
+	rl.DrawRectangleLinesEx(rec, 2, yellow)
+	rec.Width *= min(max(value, 0), 1)
+	rl.DrawRectangleRec(rec, yellow)
+}
+
+// Tells if something blinking at the given rate should be drawn now.
+// Nothing blinks when the player asked for less flashing.
+func (a *App) BlinkOn(rate float64) bool {
+	return a.config.ReduceFlashing || int(rl.GetTime()*rate)%2 == 0
+}
+
+// In two player games both scores are shown, with the high score between them
+func (a *App) ScoresHUDDraw() {
+	g := a.game
+	if g.Mode() == game.OnePlayer {
+		a.TextAt(50, 15, "SCORE")
+		a.TextAt(50, 40, "%05d", g.Score())
+		a.TextAt(570, 15, "HIGH SCORE")
+		a.TextAt(570, 40, "%05d", g.HighScore())
+		return
+	}
+
+	for i, player := range g.Players() {
+		posx := 50 + i*520
+		// the label of the player about to play blinks
+		if i != g.CurrentPlayer() || !g.TurnStarting() || a.BlinkOn(2) {
+			a.TextAt(posx, 15, "SCORE<%d>", i+1)
+		}
+		a.TextAt(posx, 40, "%05d", player.Score())
+	}
+	a.CenterTextAt(0, 15, worldWidth, "HIGH SCORE")
+	a.CenterTextAt(0, 40, worldWidth, "%05d", g.HighScore())
+}
+
+// The spare spaceships at the bottom left. In co-op games with their own
+// lives, the second player's come after the first player's, in their tint.
+func (a *App) LivesDraw() {
+	g := a.game
+	if g.Mode() != game.CoOp {
+		for i := range g.Lives() {
+			rl.DrawTextureV(a.animation(atlas.SpaceshipSprite).Frame(0), rl.Vector2{X: float32(50 * (i + 1)), Y: 745}, rl.White)
+		}
+		return
+	}
+
+	posx := float32(50)
+	for player, p := range g.Players() {
+		for range p.Lives() {
+			rl.DrawTextureV(a.animation(atlas.SpaceshipSprite).Frame(0), rl.Vector2{X: posx, Y: 745}, rgba(game.ShipTints[player]))
+			posx += 45
+		}
+		posx += 20
+	}
+}
+
+func (a *App) TurnDraw() {
+	a.DrawDialogBox("GET READY", fmt.Sprintf("PLAYER %d", a.game.CurrentPlayer()+1), "", grey)
+}
+
+// The stats of the game are shown under the box
+func (a *App) GameOverDraw() {
+	a.DrawDialogBox("GAME OVER", "PRESS ENTER TO RESTART", "PRESS ESC FOR TITLE", red)
+	played := a.achievements.Game
+	a.CenterTextAt(0, 320, worldWidth, "ALIENS %d  ACCURACY %d%%", played.AliensKilled, int(played.Accuracy()*100))
+}
+
+func (a *App) ReplayEndDraw() {
+	a.DrawDialogBox("END OF REPLAY", "PRESS ENTER TO WATCH AGAIN", "PRESS ESC TO QUIT", green)
+}
+
+func (a *App) PausedDraw() {
+	a.DimWorld()
+	a.DrawDialogBox("PAUSED", "", "PRESS P TO RESUME", grey)
+}
+
+func (a *App) LevelUpDraw() {
+	a.DrawDialogBox("CONGRATULATIONS", "YOU DEFEATED THE ALIENS", "PRESS ENTER FOR NEXT LEVEL", green)
+}
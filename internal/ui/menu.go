@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"goinvaders/internal/input"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const menuLineHeight = 45
+
+// menuItem is a line of a menu
+type menuItem struct {
+	label string
+	// current value of a setting, shown after the label,
+	// either as text or as a slider between 0 and 1
+	value  func() string
+	slider func() float32
+	// called with -1 or 1 when left or right is pressed on the item
+	adjust func(delta int)
+	// items that throw something away ask the player to confirm first
+	confirm bool
+	action  func()
+}
+
+// menu is a list of items: up and down choose an item, confirm or fire
+// run it and back leaves the menu
+type menu struct {
+	title      string
+	items      []menuItem
+	selected   int
+	confirming bool
+	back       func()
+	// width of the box around the menu, 0 for the default width
+	width int
+	// height of a line, 0 for menuLineHeight
+	lineHeight int
+}
+
+func (m *menu) lineSpacing() int {
+	if m.lineHeight == 0 {
+		return menuLineHeight
+	}
+	return m.lineHeight
+}
+
+func (a *App) HandleMenuInput(m *menu) {
+	if m.confirming {
+		if a.controls.Pressed(input.Confirm) {
+			m.confirming = false
+			m.items[m.selected].action()
+		}
+		// pause answers no as well, so that it never leaves the dialog behind
+		if a.controls.Pressed(input.Back) || a.controls.Pressed(input.Pause) {
+			m.confirming = false
+		}
+		return
+	}
+
+	count := len(m.items)
+	switch {
+	case a.controls.Pressed(input.MenuUp):
+		m.selected = (m.selected + count - 1) % count
+	case a.controls.Pressed(input.MenuDown):
+		m.selected = (m.selected + 1) % count
+	case a.controls.Pressed(input.MoveLeft) && m.items[m.selected].adjust != nil:
+		m.items[m.selected].adjust(-1)
+	case a.controls.Pressed(input.MoveRight) && m.items[m.selected].adjust != nil:
+		m.items[m.selected].adjust(1)
+	case a.controls.Pressed(input.Confirm) || a.controls.Pressed(input.Fire):
+		item := m.items[m.selected]
+		switch {
+		case item.confirm:
+			m.confirming = true
+		case item.action != nil:
+			item.action()
+		case item.adjust != nil:
+			item.adjust(1)
+		}
+	case a.controls.Pressed(input.Back):
+		m.back()
+	}
+}
+
+func (a *App) MenuDraw(m *menu) {
+	a.DimWorld()
+	width := m.width
+	if width == 0 {
+		width = 500
+	}
+	rec := a.DrawPanel(width, 110+len(m.items)*m.lineSpacing())
+	a.CenterTextAt(int(rec.X), int(rec.Y)+25, int(rec.Width), m.title)
+
+	a.MenuItemsDraw(m, int(rec.X)+60, int(rec.Y)+85)
+}
+
+// Draws the items of the menu one below the other, from the given position
+func (a *App) MenuItemsDraw(m *menu, posx, posy int) {
+	for i, item := range m.items {
+		text := item.label
+		if item.value != nil || item.slider != nil {
+			text = padRight(text, 16)
+		}
+		if item.value != nil {
+			text += item.value()
+		}
+		if i == m.selected {
+			text = "> " + text
+		} else {
+			text = "  " + text
+		}
+		a.TextAt(posx, posy+i*m.lineSpacing(), text)
+		if item.slider != nil {
+			width := int(rl.MeasureTextEx(a.font, text, 34, 2).X)
+			a.DrawSlider(posx+width+10, posy+i*m.lineSpacing(), 160, item.slider())
+		}
+	}
+
+	if m.confirming {
+		a.DrawDialogBox("ARE YOU SURE?", m.items[m.selected].label, "ENTER: YES   ESC: NO", red)
+	}
+}
+
+func padRight(text string, width int) string {
+	for len(text) < width {
+		text += " "
+	}
+	return text
+}
+
+// Darkens whatever was drawn, to show that the game is on hold
+func (a *App) DimWorld() {
+	rl.DrawRectangle(0, 0, worldWidth, worldHeight, rl.Fade(rl.Black, 0.6))
+}
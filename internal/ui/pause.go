@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"goinvaders/internal/game"
+	"goinvaders/internal/input"
+	"strings"
+)
+
+// Names of the actions on the controls screen
+var actionLabels = map[input.Action]string{
+	input.MoveLeft:    "LEFT",
+	input.MoveRight:   "RIGHT",
+	input.Fire:        "FIRE",
+	input.Pause:       "PAUSE",
+	input.ToggleMusic: "MUSIC",
+	input.ToggleSfx:   "SFX",
+	input.Confirm:     "CONFIRM",
+	input.Back:        "BACK",
+	input.MenuUp:      "MENU UP",
+	input.MenuDown:    "MENU DOWN",
+	input.MoveLeft2:   "P2 LEFT",
+	input.MoveRight2:  "P2 RIGHT",
+	input.Fire2:       "P2 FIRE",
+}
+
+func (a *App) newPauseMenu() *menu {
+	return &menu{
+		title: "PAUSED",
+		items: []menuItem{
+			{label: "RESUME", action: a.Resume},
+			{label: "RESTART", confirm: true, action: a.RestartGame},
+			{label: "SETTINGS", action: func() { a.OpenScreen(settingsScreen) }},
+			{label: "CONTROLS", action: func() { a.OpenScreen(controlsScreen) }},
+			{label: "QUIT TO TITLE", confirm: true, action: a.QuitToTitle},
+			{label: "QUIT", confirm: true, action: func() { a.game.Quit() }},
+		},
+		back: a.Resume,
+	}
+}
+
+// Pauses the game and shows the pause menu
+func (a *App) OpenPauseMenu() {
+	a.game.TogglePause()
+	a.pauseMenu.selected = 0
+	a.pauseMenu.confirming = false
+	a.screen = pauseScreen
+}
+
+func (a *App) Resume() {
+	a.game.TogglePause()
+	a.screen = playScreen
+}
+
+// Throws the current game away and starts a new one
+func (a *App) RestartGame() {
+	a.StopRecording()
+	a.game.Restart()
+	a.StartRecording()
+	a.screen = playScreen
+}
+
+// Throws the current game away and goes back to the title screen
+func (a *App) QuitToTitle() {
+	a.StopRecording()
+	a.ToTitle()
+}
+
+// Opens a screen that goes back to the current one when closed
+func (a *App) OpenScreen(s screen) {
+	a.returnScreen = a.screen
+	a.screen = s
+}
+
+func (a *App) HandleControlsInput() {
+	if a.controls.Pressed(input.Back) || a.controls.Pressed(input.Confirm) {
+		a.screen = a.returnScreen
+	}
+}
+
+func (a *App) ControlsDraw() {
+	a.DimWorld()
+	rec := a.DrawPanel(760, 700)
+	a.CenterTextAt(int(rec.X), int(rec.Y)+25, int(rec.Width), "CONTROLS")
+
+	bindings := a.controls.Bindings()
+	for i, action := range input.Actions() {
+		keys := make([]string, 0)
+		for _, key := range bindings.Keys[action] {
+			keys = append(keys, input.KeyName(key))
+		}
+		buttons := make([]string, 0)
+		for _, button := range bindings.Buttons[action] {
+			buttons = append(buttons, input.ButtonName(button))
+		}
+		// the second player uses the buttons of the first on the second gamepad
+		for first, buttonList := range bindings.Buttons {
+			if second, ok := input.SecondPlayerAction(first); ok && second == action {
+				for _, button := range buttonList {
+					buttons = append(buttons, input.ButtonName(button))
+				}
+				buttons = append(buttons, "(PAD 2)")
+			}
+		}
+		posy := int(rec.Y) + 85 + i*40
+		a.TextAt(int(rec.X)+30, posy, actionLabels[action])
+		a.TextAt(int(rec.X)+220, posy, strings.Join(keys, " "))
+		a.TextAt(int(rec.X)+490, posy, strings.Join(buttons, " "))
+	}
+	a.CenterTextAt(int(rec.X), int(rec.Y+rec.Height)-55, int(rec.Width), "PRESS ESC TO GO BACK")
+}
+
+// Draws the menus shown while the game is paused
+func (a *App) PauseDraw() {
+	switch a.screen {
+	case pauseScreen:
+		a.MenuDraw(a.pauseMenu)
+	case settingsScreen:
+		a.MenuDraw(a.settingsMenu)
+	case keysScreen:
+		a.MenuDraw(a.keysMenu)
+	case controlsScreen:
+		a.ControlsDraw()
+	default:
+		if a.game.State() == game.Paused {
+			a.PausedDraw()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"goinvaders/internal/config"
+	"goinvaders/internal/game"
+	"goinvaders/internal/scores"
+	"goinvaders/internal/stats"
+	"goinvaders/internal/storage"
+	"goinvaders/internal/tools"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+func (a *App) LoadScores() {
+	table, err := scores.Load()
+	if err != nil {
+		rl.TraceLog(rl.LogError, "Could not load the high scores: %s", err.Error())
+	}
+	a.scores = table
+}
+
+func (a *App) SaveScores() {
+	if err := a.scores.Save(); err != nil {
+		rl.TraceLog(rl.LogError, "Could not save the high scores: %s", err.Error())
+	}
+}
+
+// Loads the achievements unlocked so far, each new one is shown and saved
+func (a *App) LoadAchievements() {
+	tracker, err := stats.Load()
+	if err != nil {
+		rl.TraceLog(rl.LogError, "Could not load the achievements: %s", err.Error())
+	}
+	tracker.OnUnlock = func(achievement stats.Achievement) {
+		a.toasts = append(a.toasts, achievement.Name)
+		a.SaveAchievements()
+	}
+	a.achievements = tracker
+}
+
+func (a *App) SaveAchievements() {
+	if err := a.achievements.Save(); err != nil {
+		rl.TraceLog(rl.LogError, "Could not save the achievements: %s", err.Error())
+	}
+}
+
+// Changes a setting both for this session and in the config file.
+// The two can differ, with the command line options, so change must set
+// values decided from a.config rather than derive them from cfg.
+func (a *App) SaveConfig(change func(cfg *config.Config)) {
+	change(&a.config)
+	if err := config.Update(change); err != nil {
+		rl.TraceLog(rl.LogError, "Could not save the config: %s", err.Error())
+	}
+}
+
+// Number of replays kept in the config folder, older ones are deleted
+const maxReplays = 20
+
+func (a *App) SaveReplay(replay *game.Replay) {
+	name := fmt.Sprintf("replay-%s.girp", time.Now().Format("20060102-150405"))
+	fileName, err := tools.GetConfigPath(name)
+	if err != nil {
+		rl.TraceLog(rl.LogError, err.Error())
+		return
+	}
+	var buf bytes.Buffer
+	replay.Write(&buf)
+	if err := storage.WriteSealed(fileName, buf.Bytes()); err != nil {
+		rl.TraceLog(rl.LogError, "Could not save replay to file %s: %s", fileName, err.Error())
+		return
+	}
+	rl.TraceLog(rl.LogInfo, "Replay saved to %s", fileName)
+	pruneReplays(filepath.Dir(fileName))
+}
+
+func pruneReplays(dir string) {
+	files, err := filepath.Glob(filepath.Join(dir, "replay-*.girp"))
+	if err != nil || len(files) <= maxReplays {
+		return
+	}
+	// file names start with the date, so sorting them puts the oldest first
+	sort.Strings(files)
+	for _, file := range files[:len(files)-maxReplays] {
+		os.Remove(file)
+	}
+}
+
+func LoadReplay(fileName string) (*game.Replay, error) {
+	data, err := storage.ReadSealed(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := game.ReadReplay(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return replay, nil
+}
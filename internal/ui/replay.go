@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"goinvaders/internal/game"
+	"goinvaders/internal/input"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Every game is recorded, so that bug reports can be reproduced exactly.
+// The seed is logged too, so that it can be played again with --seed.
+func (a *App) StartRecording() {
+	a.recording = game.NewReplay(a.game)
+	rl.TraceLog(rl.LogInfo, "New game with seed %d", a.game.Seed())
+}
+
+// Saves the current recording, if there is anything worth saving
+func (a *App) StopRecording() {
+	if a.recording != nil && len(a.recording.Inputs) > 0 {
+		a.SaveReplay(a.recording)
+	}
+	a.recording = nil
+}
+
+// Starts (or restarts) watching the replay given on the command line
+func (a *App) StartPlayback() {
+	a.game = a.playback.NewGame(a.levels)
+	a.Subscribe(a.game)
+	a.playbackTick = 0
+	rl.TraceLog(rl.LogInfo, "Playing replay with seed %d (%d ticks)", a.playback.Seed, len(a.playback.Inputs))
+}
+
+func (a *App) PlaybackEnded() bool {
+	return a.game.State() == game.GameOver || a.playbackTick >= len(a.playback.Inputs)
+}
+
+// While watching a replay the player can only restart it or quit.
+// Levels follow each other without waiting for the player.
+func (a *App) HandlePlaybackInput() {
+	if a.PlaybackEnded() {
+		if a.controls.Pressed(input.Back) {
+			a.game.Quit()
+		}
+		if a.controls.Pressed(input.Confirm) {
+			a.StartPlayback()
+		}
+		return
+	}
+	if a.game.State() == game.LevelUp {
+		a.game.NextLevel()
+	}
+}
+
+// Runs a single simulation tick, with the input either recorded or played back.
+// Only the ticks where the game is running are part of the replay.
+func (a *App) Step() {
+	inputs := a.inputs
+	if a.game.State() == game.Running {
+		switch {
+		case a.playback != nil:
+			if a.PlaybackEnded() {
+				return
+			}
+			inputs = a.playback.Inputs[a.playbackTick]
+			a.playbackTick++
+		case a.demo:
+			inputs = game.TickInput{game.Autopilot(a.game)}
+		case a.recording != nil:
+			a.recording.Record(inputs)
+		}
+	}
+	a.game.Update(inputs[:]...)
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"goinvaders/internal/game"
+	"goinvaders/internal/input"
+	"goinvaders/internal/scores"
+	"strings"
+	"time"
+	"unicode"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Letters the player can choose from when entering the initials
+const initialsAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ "
+
+// Arcade style initials entry: left and right change the letter,
+// fire moves to the next one and back returns to the previous one.
+// On a keyboard the letters can also be typed directly.
+type initialsEntry struct {
+	player  int
+	letters [scores.InitialsLength]int
+	cursor  int
+}
+
+// Game modes as written in the high score table
+var scoreModes = map[game.Mode]string{
+	game.OnePlayer:  scores.OnePlayer,
+	game.TwoPlayers: scores.TwoPlayers,
+	game.CoOp:       scores.CoOp,
+	game.SharedCoOp: scores.CoOp,
+}
+
+func (e *initialsEntry) String() string {
+	var sb strings.Builder
+	for _, letter := range e.letters {
+		sb.WriteByte(initialsAlphabet[letter])
+	}
+	return sb.String()
+}
+
+// Called when the game is over: every player with a good enough score
+// is asked for their initials, one after the other
+func (a *App) EnterHighScore() {
+	a.pendingPlayers = a.pendingPlayers[:0]
+	for player := range a.game.Players() {
+		a.pendingPlayers = append(a.pendingPlayers, player)
+	}
+	a.NextHighScore()
+}
+
+// Asks the next player with a good enough score for the initials.
+// Returns false when there is nobody left to ask.
+func (a *App) NextHighScore() bool {
+	for len(a.pendingPlayers) > 0 {
+		player := a.pendingPlayers[0]
+		a.pendingPlayers = a.pendingPlayers[1:]
+		if a.scores.Qualifies(a.game.Players()[player].Score()) {
+			a.entry = initialsEntry{player: player}
+			a.screen = initialsScreen
+			return true
+		}
+	}
+	return false
+}
+
+func (a *App) SubmitHighScore() {
+	player := a.game.Players()[a.entry.player]
+	a.newRank = a.scores.Add(scores.Entry{
+		Initials: strings.TrimSpace(a.entry.String()),
+		Score:    player.Score(),
+		Level:    player.Level(),
+		Date:     time.Now(),
+		Mode:     scoreModes[a.game.Mode()],
+	})
+	a.SaveScores()
+	if !a.NextHighScore() {
+		a.screen = scoresScreen
+	}
+}
+
+func (a *App) HandleInitialsInput() {
+	e := &a.entry
+	count := len(initialsAlphabet)
+
+	if a.controls.Pressed(input.MoveLeft) {
+		e.letters[e.cursor] = (e.letters[e.cursor] + count - 1) % count
+	}
+	if a.controls.Pressed(input.MoveRight) {
+		e.letters[e.cursor] = (e.letters[e.cursor] + 1) % count
+	}
+	if a.controls.Pressed(input.Back) && e.cursor > 0 {
+		e.cursor--
+	}
+
+	next := a.controls.Pressed(input.Fire) || a.controls.Pressed(input.Confirm)
+	for char := rl.GetCharPressed(); char != 0; char = rl.GetCharPressed() {
+		// space is the fire key, which confirms the letter instead of typing it
+		if index := strings.IndexRune(initialsAlphabet, unicode.ToUpper(char)); index >= 0 && char != ' ' {
+			e.letters[e.cursor] = index
+			next = true
+		}
+	}
+
+	if next {
+		if e.cursor == len(e.letters)-1 {
+			a.SubmitHighScore()
+			return
+		}
+		e.cursor++
+	}
+}
+
+// The high scores are either shown after a game, or from the title screen
+func (a *App) HandleScoresInput() {
+	if a.game.State() == game.Idle {
+		if a.controls.AnyPressed() {
+			a.ToTitleScreen()
+		}
+		return
+	}
+
+	if a.controls.Pressed(input.Back) {
+		a.ToTitle()
+	}
+	if a.controls.Pressed(input.Confirm) {
+		a.screen = playScreen
+		a.newRank = -1
+		a.game.Restart()
+		a.StartRecording()
+	}
+}
+
+func (a *App) InitialsDraw() {
+	letters := []byte(a.entry.String())
+	// the letter being chosen blinks
+	if !a.BlinkOn(4) {
+		letters[a.entry.cursor] = '_'
+	}
+	text := strings.Join(strings.Split(string(letters), ""), " ")
+	title := "NEW HIGH SCORE!"
+	if a.game.Mode() != game.OnePlayer {
+		title = fmt.Sprintf("PLAYER %d HIGH SCORE!", a.entry.player+1)
+	}
+	a.DrawDialogBox(title, "ENTER YOUR INITIALS", text, green)
+}
+
+func (a *App) ScoresDraw(footer string) {
+	rec := rl.Rectangle{X: 75, Y: 90, Width: 650, Height: 630}
+	rl.DrawRectangleRec(rec, grey)
+	rl.DrawRectangleLinesEx(rec, 10.0, yellow)
+
+	a.CenterTextAt(int(rec.X), 110, int(rec.Width), "HIGH SCORES")
+	a.TextAt(100, 160, "%2s %-3s %5s %3s %-10s %s", "#", "WHO", "SCORE", "LVL", "DATE", "MODE")
+	for i, entry := range a.scores.Entries {
+		// the entry just added blinks
+		if i == a.newRank && !a.BlinkOn(4) {
+			continue
+		}
+		a.TextAt(100, 200+i*40, rankText(i, entry))
+	}
+	if len(a.scores.Entries) == 0 {
+		a.CenterTextAt(int(rec.X), 300, int(rec.Width), "NO SCORES YET")
+	}
+	a.CenterTextAt(int(rec.X), 660, int(rec.Width), footer)
+}
+
+func rankText(rank int, entry scores.Entry) string {
+	return fmt.Sprintf("%2d %-3s %05d %3d %s %s", rank+1, entry.Initials, entry.Score, entry.Level, entry.Date.Format("2006-01-02"), entry.Mode)
+}
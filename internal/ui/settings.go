@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"goinvaders/internal/audio"
+	"goinvaders/internal/config"
+	"goinvaders/internal/game"
+	"goinvaders/internal/input"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Change of volume for each press of left or right
+const volumeStep = 0.1
+
+// Frame rate caps offered in the settings, 0 meaning no cap
+var fpsCaps = []int32{30, 60, 120, 144, 240, 0}
+
+// Every change made in the settings is applied at once and saved
+func (a *App) newSettingsMenu() *menu {
+	back := func() { a.screen = a.returnScreen }
+	return &menu{
+		title:      "SETTINGS",
+		width:      660,
+		lineHeight: 40,
+		items: []menuItem{
+			{label: "VOLUME", slider: func() float32 { return a.config.MasterVolume }, adjust: a.AdjustMasterVolume},
+			{label: "MUSIC VOLUME", slider: func() float32 { return a.config.MusicVolume }, adjust: a.AdjustMusicVolume},
+			{label: "SFX VOLUME", slider: func() float32 { return a.config.SfxVolume }, adjust: a.AdjustSfxVolume},
+			{label: "MUSIC", value: func() string { return onOff(!a.audio.Muted(audio.Music)) }, action: a.ToggleMusic},
+			{label: "MUSIC STYLE", value: a.musicText, adjust: func(int) { a.ToggleMusicStyle() }},
+			{label: "SFX", value: func() string { return onOff(!a.audio.Muted(audio.Sfx)) }, action: a.ToggleSfx},
+			{label: "DISPLAY", value: a.displayText, action: a.ToggleFullscreen},
+			{label: "FPS CAP", value: a.fpsText, adjust: a.AdjustFPS},
+			{label: "DIFFICULTY", value: func() string { return strings.ToUpper(a.config.Difficulty) }, adjust: a.AdjustDifficulty},
+			{label: "CO-OP LIVES", value: a.coOpLivesText, adjust: func(int) {
+				shared := !a.config.SharedLives
+				a.SaveConfig(func(cfg *config.Config) { cfg.SharedLives = shared })
+			}},
+			{label: "REDUCE FLASHING", value: func() string { return onOff(a.config.ReduceFlashing) }, action: func() {
+				reduce := !a.config.ReduceFlashing
+				a.SaveConfig(func(cfg *config.Config) { cfg.ReduceFlashing = reduce })
+			}},
+			{label: "REDUCE MOTION", value: func() string { return onOff(a.config.ReduceMotion) }, action: func() {
+				reduce := !a.config.ReduceMotion
+				a.SaveConfig(func(cfg *config.Config) { cfg.ReduceMotion = reduce })
+			}},
+			{label: "KEY BINDINGS", action: a.OpenKeysMenu},
+			{label: "BACK", action: back},
+		},
+		back: back,
+	}
+}
+
+func onOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
+
+func stepVolume(volume float32, delta int) float32 {
+	volume += float32(delta) * volumeStep
+	// round to the step, so that repeated steps land on 0 and 1 exactly
+	volume = float32(math.Round(float64(volume)/volumeStep) * volumeStep)
+	return min(max(volume, 0), 1)
+}
+
+// The volumes of the sound effects play the laser sound,
+// so that the new volume can be heard
+func (a *App) AdjustMasterVolume(delta int) {
+	volume := stepVolume(a.config.MasterVolume, delta)
+	a.audio.SetVolume(audio.Master, volume)
+	a.SaveConfig(func(cfg *config.Config) { cfg.MasterVolume = volume })
+	a.audio.Play(audio.Laser)
+}
+
+func (a *App) AdjustMusicVolume(delta int) {
+	volume := stepVolume(a.config.MusicVolume, delta)
+	a.audio.SetVolume(audio.Music, volume)
+	a.SaveConfig(func(cfg *config.Config) { cfg.MusicVolume = volume })
+}
+
+// Switches between the music track and the march of the fleet
+func (a *App) ToggleMusicStyle() {
+	music := config.MarchMusic
+	if a.config.Music == config.MarchMusic {
+		music = config.TrackMusic
+	}
+	a.SaveConfig(func(cfg *config.Config) { cfg.Music = music })
+	a.ApplyMusic()
+}
+
+func (a *App) musicText() string {
+	if a.config.Music == config.MarchMusic {
+		return "MARCH"
+	}
+	return "TRACK"
+}
+
+func (a *App) AdjustSfxVolume(delta int) {
+	volume := stepVolume(a.config.SfxVolume, delta)
+	a.audio.SetVolume(audio.Sfx, volume)
+	a.SaveConfig(func(cfg *config.Config) { cfg.SfxVolume = volume })
+	a.audio.Play(audio.Laser)
+}
+
+// Switches between a window and a borderless window covering the screen
+func SetFullscreen(on bool) {
+	if on != rl.IsWindowState(rl.FlagBorderlessWindowedMode) {
+		rl.ToggleBorderlessWindowed()
+	}
+}
+
+func (a *App) ToggleFullscreen() {
+	full := !a.config.Fullscreen
+	a.SaveConfig(func(cfg *config.Config) { cfg.Fullscreen = full })
+	SetFullscreen(full)
+}
+
+func (a *App) displayText() string {
+	if a.config.Fullscreen {
+		return "FULLSCREEN"
+	}
+	return "WINDOW"
+}
+
+func (a *App) AdjustFPS(delta int) {
+	index := slices.Index(fpsCaps, a.config.FPS)
+	if index < 0 {
+		// a cap set in the config file that is not in the list
+		index = slices.Index(fpsCaps, 60)
+	}
+	fps := fpsCaps[(index+delta+len(fpsCaps))%len(fpsCaps)]
+	rl.SetTargetFPS(fps)
+	a.SaveConfig(func(cfg *config.Config) { cfg.FPS = fps })
+}
+
+func (a *App) fpsText() string {
+	if a.config.FPS == 0 {
+		return "NO LIMIT"
+	}
+	return strconv.Itoa(int(a.config.FPS))
+}
+
+// The difficulty is used from the next game on,
+// or at once when the game has not started yet
+func (a *App) AdjustDifficulty(delta int) {
+	current, _ := game.ParseDifficulty(a.config.Difficulty)
+	difficulties := game.Difficulties()
+	index := slices.Index(difficulties, current)
+	difficulty := difficulties[(index+delta+len(difficulties))%len(difficulties)]
+
+	a.SaveConfig(func(cfg *config.Config) { cfg.Difficulty = difficulty.String() })
+	a.game.SetDifficulty(difficulty)
+	if a.game.State() == game.Idle {
+		a.game.Reset(a.game.Seed())
+	}
+}
+
+func (a *App) coOpLivesText() string {
+	if a.config.SharedLives {
+		return "SHARED"
+	}
+	return "OWN"
+}
+
+func (a *App) OpenKeysMenu() {
+	a.keysMenu = a.newKeysMenu()
+	a.screen = keysScreen
+}
+
+// Lists the keys of every action. Choosing an action waits for
+// the new key, which then replaces the keys of the action.
+func (a *App) newKeysMenu() *menu {
+	back := func() { a.screen = settingsScreen }
+	items := make([]menuItem, 0)
+	for _, action := range input.Actions() {
+		items = append(items, menuItem{
+			label: actionLabels[action],
+			value: func() string {
+				if a.rebinding && a.rebindAction == action {
+					return "PRESS A KEY"
+				}
+				return a.keyNames(action)
+			},
+			action: func() {
+				a.rebinding = true
+				a.rebindAction = action
+			},
+		})
+	}
+	items = append(items,
+		menuItem{label: "DEFAULT KEYS", confirm: true, action: func() { a.SetBindings(input.DefaultBindings()) }},
+		menuItem{label: "BACK", action: back},
+	)
+	return &menu{title: "KEY BINDINGS", width: 720, lineHeight: 40, items: items, back: back}
+}
+
+func (a *App) keyNames(action input.Action) string {
+	names := make([]string, 0)
+	for _, key := range a.controls.Bindings().Keys[action] {
+		names = append(names, input.KeyName(key))
+	}
+	return strings.Join(names, " ")
+}
+
+// Waits for the key to bind to the action being changed.
+// Esc leaves the keys as they were.
+func (a *App) HandleKeysInput() {
+	if !a.rebinding {
+		a.HandleMenuInput(a.keysMenu)
+		return
+	}
+
+	key := rl.GetKeyPressed()
+	if key == 0 {
+		return
+	}
+	a.rebinding = false
+	if key == rl.KeyEscape {
+		return
+	}
+	bindings := a.controls.Bindings()
+	keys := bindings.KeyNames()
+	keys[a.rebindAction.String()] = []string{input.KeyName(key)}
+	bindings, err := bindings.WithKeys(keys)
+	if err != nil {
+		rl.TraceLog(rl.LogError, "Could not bind the key: %s", err.Error())
+		return
+	}
+	a.SetBindings(bindings)
+}
+
+func (a *App) SetBindings(bindings input.Bindings) {
+	a.controls.SetBindings(bindings)
+	a.SaveConfig(func(cfg *config.Config) { cfg.Keys = bindings.KeyNames() })
+}
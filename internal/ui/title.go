@@ -0,0 +1,170 @@
+package ui
+
+import (
+	"goinvaders/internal/assets"
+	"goinvaders/internal/assets/atlas"
+	"goinvaders/internal/game"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Attract mode: when nobody touches the controls on the title screen,
+// the high scores are shown, then a demo round plays by itself,
+// then the title comes back. Any action returns to the title.
+const (
+	// Seconds on the title screen before the attract mode starts
+	attractDelay float32 = 15
+	// Seconds the high scores are shown
+	attractScoresTime float32 = 8
+	// Longest demo round, in seconds
+	demoTime float32 = 40
+)
+
+func (a *App) newTitleMenu() *menu {
+	return &menu{
+		title: "GO INVADERS",
+		items: []menuItem{
+			{label: "1 PLAYER", action: func() { a.StartGame(game.OnePlayer) }},
+			{label: "2 PLAYERS", action: func() { a.StartGame(game.TwoPlayers) }},
+			{label: "CO-OP", action: a.StartCoOp},
+			{label: "HIGH SCORES", action: a.ShowScores},
+			{label: "SETTINGS", action: func() { a.OpenScreen(settingsScreen) }},
+			{label: "CONTROLS", action: func() { a.OpenScreen(controlsScreen) }},
+			{label: "QUIT", action: func() { a.game.Quit() }},
+		},
+		back:       func() {},
+		lineHeight: 40,
+	}
+}
+
+// Goes back to the title screen, with a new game ready to start
+func (a *App) ToTitle() {
+	a.game.Reset(a.game.NextSeed())
+	a.titleMenu.selected = 0
+	a.newRank = -1
+	a.ToTitleScreen()
+}
+
+// The game waiting on the title screen is set up again when the mode changes
+func (a *App) StartGame(mode game.Mode) {
+	if mode != a.game.Mode() {
+		a.game.SetMode(mode)
+		a.game.Reset(a.game.Seed())
+	}
+	a.game.Start()
+	a.StartRecording()
+	a.screen = playScreen
+}
+
+// Co-op games share the lives or not, as chosen in the settings
+func (a *App) StartCoOp() {
+	if a.config.SharedLives {
+		a.StartGame(game.SharedCoOp)
+	} else {
+		a.StartGame(game.CoOp)
+	}
+}
+
+// Shows the high scores from the title screen
+func (a *App) ShowScores() {
+	a.screen = scoresScreen
+	a.screenTime = 0
+}
+
+// Plays a demo round with a random seed, without recording it.
+// The game waiting on the title screen is set up again afterwards.
+func (a *App) StartDemo() {
+	a.titleSeed = a.game.Seed()
+	a.game.SetMode(game.OnePlayer)
+	a.game.Reset(game.NewSeed())
+	a.game.Start()
+	a.demo = true
+	a.screen = demoScreen
+	a.screenTime = 0
+}
+
+func (a *App) StopDemo() {
+	a.demo = false
+	a.game.Reset(a.titleSeed)
+	// the demo score must not show up as the high score
+	a.game.SetHighScore(a.scores.Best())
+	a.ToTitleScreen()
+}
+
+// Shows the title screen again, without changing the game
+func (a *App) ToTitleScreen() {
+	a.attract = false
+	a.screen = titleScreen
+	a.screenTime = 0
+}
+
+func (a *App) HandleTitleInput() {
+	if a.controls.AnyPressed() {
+		a.screenTime = 0
+	}
+	a.HandleMenuInput(a.titleMenu)
+}
+
+func (a *App) HandleDemoInput() {
+	if a.controls.AnyPressed() {
+		a.StopDemo()
+	}
+}
+
+// Moves the attract mode along, frameTime being the seconds since the last frame
+func (a *App) UpdateAttract(frameTime float32) {
+	a.screenTime += frameTime
+	switch a.screen {
+	case titleScreen:
+		if a.screenTime > attractDelay {
+			a.ShowScores()
+			a.attract = true
+		}
+	case scoresScreen:
+		if a.attract && a.screenTime > attractScoresTime {
+			a.StartDemo()
+		}
+	case demoScreen:
+		if a.game.State() != game.Running || a.screenTime > demoTime {
+			a.StopDemo()
+		}
+	}
+}
+
+func (a *App) TitleDraw() {
+	a.DimWorld()
+
+	logoSize := float32(96)
+	logo := "GO INVADERS"
+	width := rl.MeasureTextEx(a.font, logo, logoSize, 4).X
+	rl.DrawTextEx(a.font, logo, rl.Vector2{X: (worldWidth - width) / 2, Y: 80}, logoSize, 4, assets.Yellow)
+
+	// the prompt blinks like on the arcade cabinets
+	if a.BlinkOn(2) {
+		a.CenterTextAt(0, 185, worldWidth, "PRESS ENTER TO START")
+	}
+
+	a.CenterTextAt(0, 230, worldWidth, "*SCORE ADVANCE TABLE*")
+	a.LegendDraw(a.animation(atlas.MysterySprite).Frame(0), 270, "= %d POINTS", game.MysteryShipScore)
+	for alienType := int32(3); alienType >= 1; alienType-- {
+		posy := 270 + int(4-alienType)*40
+		a.LegendDraw(a.animation(atlas.AlienSprite(alienType)).Frame(int(rl.GetTime())), posy, "= %d POINTS", game.AlienScore(alienType))
+	}
+
+	a.MenuItemsDraw(a.titleMenu, 280, 440)
+}
+
+// Draws a line of the score legend: the sprite, then the text
+func (a *App) LegendDraw(image rl.Texture2D, posy int, text string, args ...any) {
+	posx := float32(330 - image.Width)
+	rl.DrawTextureV(image, rl.Vector2{X: posx, Y: float32(posy) + float32(34-image.Height)/2}, rl.White)
+	a.TextAt(350, posy, text, args...)
+}
+
+// Footer of the high score table, which tells how to leave it
+func (a *App) scoresFooter() string {
+	if a.game.State() == game.Idle {
+		return "PRESS ESC TO GO BACK"
+	}
+	return "ENTER: PLAY AGAIN   ESC: TITLE"
+}
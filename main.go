@@ -1,41 +1,117 @@
 package main
 
-//go:generate embed -verbose -exclude_dir src -include ttf,png,xml,ogg -byte all internal/assets
+//go:generate embed -verbose -exclude_dir src -include ttf,png,xml,ogg,txt -byte all internal/assets
 
 import (
-	"goinvaders/internal/game"
+	"flag"
+	"fmt"
+	"goinvaders/internal/audio"
+	"goinvaders/internal/config"
+	"goinvaders/internal/input"
+	"goinvaders/internal/ui"
+	"os"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
-const (
-	offset       = 50
-	windowWidth  = 750
-	windowHeight = 700
-	windowTitle  = "Golang Space Invaders"
-)
+const windowTitle = "Golang Space Invaders"
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
 
 func main() {
+	// The command line overrides the config file for this session only.
+	// It is parsed first, so that --help works even with a broken file.
+	cfg := config.Default()
+	var opts ui.Options
+	flag.Uint64Var(&opts.Seed, "seed", 0, "seed of the random generator, to play the same game again (0 = random)")
+	replayFile := flag.String("replay", "", "watch a replay file instead of playing")
+	flag.Var(int32Flag{&cfg.FPS}, "fps", "frame rate cap, the game speed does not depend on it (0 = no limit)")
+	flag.Var(int32Flag{&cfg.WindowWidth}, "width", "window width")
+	flag.Var(int32Flag{&cfg.WindowHeight}, "height", "window height")
+	flag.Var(float32Flag{&cfg.MasterVolume}, "volume", "volume of all the sounds, between 0 and 1")
+	flag.Var(float32Flag{&cfg.MusicVolume}, "music-volume", "music volume, between 0 and 1")
+	flag.StringVar(&cfg.Music, "music", cfg.Music, "track for the music track, march for the four note march of the fleet")
+	flag.BoolVar(&cfg.MuteMusic, "mute-music", cfg.MuteMusic, "start with the music off")
+	flag.BoolVar(&cfg.MuteSfx, "mute-sfx", cfg.MuteSfx, "start with the sound effects off")
+	noAudio := flag.Bool("no-audio", false, "play without sound, even when there is an audio device")
+	flag.BoolVar(&cfg.Fullscreen, "fullscreen", cfg.Fullscreen, "cover the whole screen")
+	flag.StringVar(&cfg.Difficulty, "difficulty", cfg.Difficulty, "easy, normal or hard")
+	flag.Parse()
+
+	given := make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		given[f.Name] = f.Value.String()
+	})
+	loaded, err := config.Load()
+	if err != nil {
+		fail("Invalid config file %s", err)
+	}
+	// the flags point into cfg: set them again over the loaded config
+	cfg = loaded
+	for name, value := range given {
+		if err := flag.Set(name, value); err != nil {
+			fail("Invalid option: %s", err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fail("Invalid option: %s", err)
+	}
+	opts.Config = cfg
+
+	opts.Bindings, err = input.DefaultBindings().WithKeys(cfg.Keys)
+	if err != nil {
+		fail("Invalid keys in the config file: %s", err)
+	}
+
+	opts.Levels, err = config.LoadLevels()
+	if err != nil {
+		fail("Invalid level file %s", err)
+	}
+
+	if *replayFile != "" {
+		replay, err := ui.LoadReplay(*replayFile)
+		if err != nil {
+			fail("Could not load the replay: %s", err)
+		}
+		if err := replay.CheckLevels(opts.Levels); err != nil {
+			fail("Could not play the replay: %s", err)
+		}
+		opts.Replay = replay
+	}
 
-	rl.InitWindow(windowWidth+offset, windowHeight+2*offset, windowTitle)
+	rl.InitWindow(cfg.WindowWidth, cfg.WindowHeight, windowTitle)
 	defer rl.CloseWindow()
+	// Esc opens the pause menu instead of closing the window
+	rl.SetExitKey(rl.KeyNull)
+	ui.SetFullscreen(cfg.Fullscreen)
 
-	rl.InitAudioDevice()
-	if !rl.IsAudioDeviceReady() {
-		rl.TraceLog(rl.LogError, "Audio device not ready")
+	// Without an audio device the game is played in silence
+	opts.Audio = &audio.Null{}
+	if !*noAudio {
+		rl.InitAudioDevice()
+		if rl.IsAudioDeviceReady() {
+			opts.Audio = &ui.RaylibAudio{}
+			defer rl.CloseAudioDevice()
+		} else {
+			rl.TraceLog(rl.LogError, "Audio device not ready, playing without sound")
+		}
 	}
-	defer rl.CloseAudioDevice()
 
-	rl.SetTargetFPS(60)
+	rl.SetTargetFPS(cfg.FPS)
 	rl.SetTraceLogLevel(rl.LogInfo)
 
-	game := game.New()
+	app := ui.New(opts)
 
-	for !game.ShouldQuit() {
+	for !app.ShouldQuit() {
 		rl.BeginDrawing()
-		game.HandleInput()
-		game.Update()
-		game.Draw()
+		app.HandleInput()
+		app.Update(rl.GetFrameTime())
+		app.Draw()
 		rl.EndDrawing()
 	}
+	app.Close()
 }